@@ -1,12 +1,17 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/TV4/vimond/restapi"
@@ -59,12 +64,30 @@ func main() {
 		cmdAssets(client, args)
 	case "current-orders":
 		cmdCurrentOrders(client, args)
+	case "epg":
+		cmdEPG(client, args)
+	case "images":
+		cmdImages(client, args)
+	case "order":
+		cmdOrder(client, args)
+	case "order-history":
+		cmdOrderHistory(client, args)
 	case "orders":
 		cmdOrders(client, args)
 	case "platforms":
 		cmdPlatforms(client)
+	case "publish":
+		cmdPublish(client, args)
+	case "products":
+		cmdProducts(client, args)
+	case "search-assets":
+		cmdSearchAssets(client, args)
+	case "users":
+		cmdUsers(client, args)
 	case "video-files":
 		cmdVideoFiles(client, args)
+	case "vouchers":
+		cmdVouchers(client, args)
 	default:
 		die("unknown command %q", cmd)
 	}
@@ -76,9 +99,35 @@ func printUsage() {
   Commands
     assets <platform> <ids>...           Fetches one or more assets
     current-orders <platform> <user-id>  Fetches current orders for the given user
+    epg <platform> <channel-id> [-from=<time>] [-to=<time>]
+                                         Prints the EPG of a channel as a timeline, flagging gaps and overlaps
+    images <platform> <asset-id> [list]  Lists the image pack of the given asset
+    images <platform> <asset-id> upload -type=<type> [-default] <file>
+                                         Uploads an image to the image pack of the given asset
+    images <platform> <asset-id> rm [-y] <image-ids>...
+                                         Deletes images from the image pack of the given asset
+    order <action> <platform> <id> [-y]  Changes an order, action is one of:
+                                           terminate [-now]  Terminates the order at the end of the period, or now
+                                           cancel-renew      Cancels the auto renewal of the order
+                                           reactivate        Reactivates the order
+    order-history <platform> <user-id>   Fetches all orders, including lapsed ones, for the given user
     orders <platform> <ids>...           Fetches one or more orders
     platforms                            Lists available platforms
-    video-files <ids>...                 Fetches video file data for the given asset(s)`)
+    products <platform>                  Prints the product catalog as a tree
+    publish list <asset-ids>...          Fetches the publishing windows of the given asset(s)
+    publish set <platform> [-start=<time>] [-end=<time>] [-geo=<regions>] [-product-group=<id>] [-y] <asset-ids>...
+                                         Sets the publishing window of the given asset(s) on a platform, changing only the given fields
+    publish rm <platform> [-y] <asset-ids>...
+                                         Unpublishes the given asset(s) from a platform
+    search-assets <platform> [<flags>]   Searches assets, printing one JSON object per line
+    users <platform> <users>...          Fetches users by ID, email or username
+    video-files [-format=json|table] [-media-format=<format>] <ids>...
+                                         Fetches video file data for the given asset(s)
+    vouchers show <platform> <codes>...  Fetches one or more vouchers
+    vouchers validate <platform> <product-payment-id> <codes>...
+                                         Checks that the vouchers can be redeemed
+    vouchers generate <platform> [<flags>]
+                                         Generates vouchers, printed as CSV`)
 	fmt.Fprintln(os.Stderr)
 }
 
@@ -132,6 +181,284 @@ func cmdCurrentOrders(client *restapi.Client, args []string) {
 	json.NewEncoder(os.Stdout).Encode(res)
 }
 
+func cmdEPG(client *restapi.Client, args []string) {
+	fs := flag.NewFlagSet("epg", flag.ExitOnError)
+
+	fFrom := fs.String("from", "", "Start of the time window (RFC 3339), defaults to now")
+	fTo := fs.String("to", "", "End of the time window (RFC 3339), defaults to 24 hours after -from")
+
+	args = parseFlags(fs, args)
+
+	if len(args) != 2 {
+		die("need platform and channel ID")
+	}
+
+	platform, channelID := args[0], args[1]
+
+	from := parseTimeFlag("from", *fFrom)
+	if from.IsZero() {
+		from = time.Now()
+	}
+
+	to := parseTimeFlag("to", *fTo)
+	if to.IsZero() {
+		to = from.Add(24 * time.Hour)
+	}
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancelCtx()
+
+	programs, err := client.Programs(ctx, platform, channelID, from, to)
+	if err != nil {
+		die("error fetching programs (%s): %v", channelID, err)
+	}
+
+	printTimeline(programs)
+}
+
+// printTimeline prints programs ordered by start time, grouped by day, with
+// gaps and overlaps between consecutive programs marked with !!.
+func printTimeline(programs []restapi.Program) {
+	gaps := map[string]time.Duration{}
+
+	for _, gap := range restapi.ScheduleGaps(programs) {
+		gaps[gap.After.ID] = gap.Duration
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	var day string
+
+	for _, p := range programs {
+		start, end := p.Start.Local(), p.End.Local()
+
+		if d := start.Format("Mon 2006-01-02"); d != day {
+			w.Flush()
+
+			if day != "" {
+				fmt.Println()
+			}
+
+			fmt.Println(d)
+			day = d
+		}
+
+		if d, ok := gaps[p.ID]; ok && d > 0 {
+			fmt.Fprintf(w, "\t\t!! gap of %s\t\t\n", formatDuration(d))
+		} else if ok {
+			fmt.Fprintf(w, "\t\t!! overlaps previous by %s\t\t\n", formatDuration(-d))
+		}
+
+		var flags []string
+
+		if p.Live {
+			flags = append(flags, "live")
+		}
+
+		if p.AssetID != "" {
+			flags = append(flags, "asset "+p.AssetID)
+		}
+
+		fmt.Fprintf(w, "  %s-%s\t%s\t%s\t%s\t(program %s)\n", start.Format("15:04"), end.Format("15:04"), formatDuration(p.Duration()), p.Title, strings.Join(flags, ", "), p.ID)
+	}
+
+	w.Flush()
+}
+
+// formatDuration formats d in hours and minutes, such as 1h05m or 45m.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+
+	return fmt.Sprintf("%dh%02dm", d/time.Hour, d%time.Hour/time.Minute)
+}
+
+func cmdImages(client *restapi.Client, args []string) {
+	if len(args) < 2 {
+		die("need platform and asset ID")
+	}
+
+	platform, assetID := args[0], args[1]
+
+	action := "list"
+
+	if len(args) > 2 {
+		action = args[2]
+		args = args[3:]
+	} else {
+		args = nil
+	}
+
+	fs := flag.NewFlagSet("images "+action, flag.ExitOnError)
+
+	fType := fs.String("type", "", "Image type, such as poster or thumbnail")
+	fDefault := fs.Bool("default", false, "Make the uploaded image the default image of its type")
+	fYes := fs.Bool("y", false, "Do not ask for confirmation")
+
+	args = parseFlags(fs, args)
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancelCtx()
+
+	switch action {
+	case "list":
+		images, err := client.AssetImages(ctx, platform, assetID)
+		if err != nil {
+			die("error fetching images (%s): %v", assetID, err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(w, "ID\tTYPE\tDEFAULT\tSIZE\tURL")
+
+		for _, image := range images {
+			def := ""
+			if image.Default {
+				def = "*"
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%dx%d\t%s\n", image.ID, image.Type, def, image.Width, image.Height, image.URL)
+		}
+
+		w.Flush()
+	case "upload":
+		if len(args) != 1 {
+			die("need exactly one file to upload")
+		}
+
+		if *fType == "" {
+			die("need image type")
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			die("error opening image: %v", err)
+		}
+		defer f.Close()
+
+		image, err := client.UploadAssetImage(ctx, platform, assetID, *fType, filepath.Base(args[0]), f)
+		if err != nil {
+			die("error uploading image (%s): %v", args[0], err)
+		}
+
+		if *fDefault {
+			if err := client.SetDefaultAssetImage(ctx, platform, assetID, image.ID); err != nil {
+				die("error setting default image (%s): %v", image.ID, err)
+			}
+
+			image.Default = true
+		}
+
+		json.NewEncoder(os.Stdout).Encode(image)
+	case "rm":
+		if len(args) < 1 {
+			die("need at least one image ID")
+		}
+
+		if !*fYes && !confirm(fmt.Sprintf("Delete %d image(s) of asset %s?", len(args), assetID)) {
+			fmt.Fprintln(os.Stderr, "aborted")
+			os.Exit(1)
+		}
+
+		for _, id := range args {
+			if err := client.DeleteAssetImage(ctx, platform, assetID, id); err != nil {
+				die("error deleting image (%s): %v", id, err)
+			}
+		}
+	default:
+		die("unknown images action %q", action)
+	}
+}
+
+func cmdOrder(client *restapi.Client, args []string) {
+	if len(args) < 1 {
+		die("need order action")
+	}
+
+	action := args[0]
+
+	fs := flag.NewFlagSet("order "+action, flag.ExitOnError)
+
+	fYes := fs.Bool("y", false, "Do not ask for confirmation")
+	fNow := fs.Bool("now", false, "Terminate immediately instead of at the end of the period")
+
+	args = parseFlags(fs, args[1:])
+
+	if len(args) != 2 {
+		die("need platform and order ID")
+	}
+
+	platform := args[0]
+	orderID := args[1]
+
+	var (
+		prompt string
+		do     func(ctx context.Context) (*restapi.Order, error)
+	)
+
+	switch action {
+	case "terminate":
+		when := "at the end of the period"
+		if *fNow {
+			when = "immediately"
+		}
+
+		prompt = fmt.Sprintf("Terminate order %s on %s %s?", orderID, platform, when)
+		do = func(ctx context.Context) (*restapi.Order, error) {
+			return client.TerminateOrder(ctx, platform, orderID, *fNow)
+		}
+	case "cancel-renew":
+		prompt = fmt.Sprintf("Cancel auto renewal of order %s on %s?", orderID, platform)
+		do = func(ctx context.Context) (*restapi.Order, error) {
+			return client.CancelAutoRenew(ctx, platform, orderID)
+		}
+	case "reactivate":
+		prompt = fmt.Sprintf("Reactivate order %s on %s?", orderID, platform)
+		do = func(ctx context.Context) (*restapi.Order, error) {
+			return client.ReactivateOrder(ctx, platform, orderID)
+		}
+	default:
+		die("unknown order action %q", action)
+	}
+
+	if !*fYes && !confirm(prompt) {
+		fmt.Fprintln(os.Stderr, "aborted")
+		os.Exit(1)
+	}
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancelCtx()
+
+	res, err := do(ctx)
+	if err != nil {
+		die("error changing order (%s): %v", orderID, err)
+	}
+
+	json.NewEncoder(os.Stdout).Encode(res)
+}
+
+func cmdOrderHistory(client *restapi.Client, args []string) {
+	if len(args) != 2 {
+		die("need platform and user ID")
+	}
+
+	platform := args[0]
+	userID := args[1]
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancelCtx()
+
+	res, err := client.OrderHistory(ctx, platform, userID)
+	if err != nil {
+		die("error fetching order history: %v", err)
+	}
+
+	json.NewEncoder(os.Stdout).Encode(res)
+}
+
 func cmdOrders(client *restapi.Client, args []string) {
 	if len(args) < 2 {
 		die("need platform and at least one order ID")
@@ -165,12 +492,296 @@ func cmdPlatforms(client *restapi.Client) {
 	json.NewEncoder(os.Stdout).Encode(res)
 }
 
-func cmdVideoFiles(client *restapi.Client, args []string) {
+func cmdProducts(client *restapi.Client, args []string) {
+	if len(args) != 1 {
+		die("need platform")
+	}
+
+	platform := args[0]
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancelCtx()
+
+	groups, err := client.ProductGroups(ctx, platform)
+	if err != nil {
+		die("error fetching product groups: %v", err)
+	}
+
+	for _, g := range groups {
+		fmt.Printf("%s (product group %s)\n", g.Name, g.ID)
+
+		products, err := client.Products(ctx, platform, g.ID)
+		if err != nil {
+			die("error fetching products (%s): %v", g.ID, err)
+		}
+
+		for _, p := range products {
+			fmt.Printf("  %s (product %s)\n", p.Name, p.ID)
+
+			payments, err := client.ProductPayments(ctx, platform, p.ID)
+			if err != nil {
+				die("error fetching product payments (%s): %v", p.ID, err)
+			}
+
+			for _, pp := range payments {
+				fmt.Printf("    %s (product payment %s): %.2f %s per %s", pp.Description, pp.ID, pp.Price, pp.Currency, pp.Period)
+
+				if pp.TrialPeriod != "" {
+					fmt.Printf(", trial %s for %.2f %s", pp.TrialPeriod, pp.TrialPrice, pp.Currency)
+				}
+
+				if pp.AutoRenew {
+					fmt.Print(", auto renew")
+				}
+
+				if !pp.Enabled {
+					fmt.Print(", disabled")
+				}
+
+				fmt.Printf(", payment provider %s\n", pp.PaymentProviderID)
+			}
+		}
+	}
+}
+
+func cmdPublish(client *restapi.Client, args []string) {
 	if len(args) < 1 {
+		die("need publish action")
+	}
+
+	action := args[0]
+
+	fs := flag.NewFlagSet("publish "+action, flag.ExitOnError)
+
+	fStart := fs.String("start", "", "Start of the publishing window (RFC 3339)")
+	fEnd := fs.String("end", "", "End of the publishing window (RFC 3339)")
+	fGeo := fs.String("geo", "", "Comma separated geo regions, such as se,no")
+	fProductGroup := fs.String("product-group", "", "Product group ID")
+	fYes := fs.Bool("y", false, "Do not ask for confirmation")
+
+	args = parseFlags(fs, args[1:])
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancelCtx()
+
+	if action == "list" {
+		if len(args) < 1 {
+			die("need at least one asset ID")
+		}
+
+		for _, id := range args {
+			res, err := client.PublishingItems(ctx, id)
+			if err != nil {
+				die("error fetching publishing items (%s): %v", id, err)
+			}
+
+			json.NewEncoder(os.Stdout).Encode(res)
+		}
+
+		return
+	}
+
+	if len(args) < 2 {
+		die("need platform and at least one asset ID")
+	}
+
+	platform := args[0]
+	ids := args[1:]
+
+	switch action {
+	case "set":
+		start := parseTimeFlag("start", *fStart)
+		end := parseTimeFlag("end", *fEnd)
+
+		var geo []string
+
+		if *fGeo != "" {
+			geo = strings.Split(*fGeo, ",")
+		}
+
+		// Only the fields of the flags given are changed, so that
+		// existing publishing items keep the rest.
+		set := map[string]bool{}
+
+		fs.Visit(func(f *flag.Flag) {
+			set[f.Name] = true
+		})
+
+		if !set["start"] && !set["end"] && !set["geo"] && !set["product-group"] {
+			die("need at least one of -start, -end, -geo and -product-group")
+		}
+
+		if !*fYes && !confirm(fmt.Sprintf("Set publishing window of %d asset(s) on %s?", len(ids), platform)) {
+			fmt.Fprintln(os.Stderr, "aborted")
+			os.Exit(1)
+		}
+
+		for _, id := range ids {
+			items, err := client.PublishingItems(ctx, id)
+			if err != nil {
+				die("error fetching publishing items (%s): %v", id, err)
+			}
+
+			item := restapi.PublishingItem{AssetID: id, Platform: platform}
+
+			for _, existing := range items {
+				if existing.Platform == platform {
+					item = existing
+					break
+				}
+			}
+
+			if set["start"] {
+				item.Publish = start
+			}
+
+			if set["end"] {
+				item.Expire = end
+			}
+
+			if set["geo"] {
+				item.GeoRegions = geo
+			}
+
+			if set["product-group"] {
+				item.ProductGroupID = *fProductGroup
+			}
+
+			var res *restapi.PublishingItem
+
+			if item.ID == "" {
+				res, err = client.CreatePublishingItem(ctx, item)
+			} else {
+				res, err = client.UpdatePublishingItem(ctx, item)
+			}
+
+			if err != nil {
+				die("error setting publishing window (%s): %v", id, err)
+			}
+
+			json.NewEncoder(os.Stdout).Encode(res)
+		}
+	case "rm":
+		if !*fYes && !confirm(fmt.Sprintf("Unpublish %d asset(s) from %s?", len(ids), platform)) {
+			fmt.Fprintln(os.Stderr, "aborted")
+			os.Exit(1)
+		}
+
+		for _, id := range ids {
+			if err := client.Unpublish(ctx, platform, id); err != nil {
+				die("error unpublishing asset (%s): %v", id, err)
+			}
+		}
+	default:
+		die("unknown publish action %q", action)
+	}
+}
+
+func cmdSearchAssets(client *restapi.Client, args []string) {
+	if len(args) < 1 {
+		die("need platform")
+	}
+
+	platform := args[0]
+
+	fs := flag.NewFlagSet("search-assets", flag.ExitOnError)
+
+	fText := fs.String("text", "", "Free text query")
+	fCategory := fs.String("category", "", "Category ID")
+	fChannel := fs.String("channel", "", "Channel ID")
+	fCreatedFrom := fs.String("created-from", "", "Earliest create time (RFC 3339)")
+	fCreatedTo := fs.String("created-to", "", "Latest create time (RFC 3339)")
+	fBroadcastFrom := fs.String("broadcast-from", "", "Earliest live broadcast time (RFC 3339)")
+	fBroadcastTo := fs.String("broadcast-to", "", "Latest live broadcast time (RFC 3339)")
+	fPublished := fs.String("published", "", "Only published (true) or unpublished (false) assets")
+	fArchived := fs.String("archived", "", "Only archived (true) or unarchived (false) assets")
+	fSort := fs.String("sort", "", "Sort field, prefixed with - for descending order")
+	fLimit := fs.Int("limit", 0, "Maximum number of assets to print, 0 for all")
+
+	fs.Parse(args[1:])
+
+	query := restapi.AssetQuery{
+		Text:          *fText,
+		CategoryID:    *fCategory,
+		ChannelID:     *fChannel,
+		CreatedFrom:   parseTimeFlag("created-from", *fCreatedFrom),
+		CreatedTo:     parseTimeFlag("created-to", *fCreatedTo),
+		BroadcastFrom: parseTimeFlag("broadcast-from", *fBroadcastFrom),
+		BroadcastTo:   parseTimeFlag("broadcast-to", *fBroadcastTo),
+		Published:     parseBoolFlag("published", *fPublished),
+		Archived:      parseBoolFlag("archived", *fArchived),
+		Sort:          *fSort,
+	}
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancelCtx()
+
+	enc := json.NewEncoder(os.Stdout)
+
+	var n int
+
+	for asset, err := range client.SearchAssets(ctx, platform, query) {
+		if err != nil {
+			die("error searching assets: %v", err)
+		}
+
+		enc.Encode(asset)
+
+		if n++; *fLimit > 0 && n >= *fLimit {
+			break
+		}
+	}
+}
+
+func cmdUsers(client *restapi.Client, args []string) {
+	if len(args) < 2 {
+		die("need platform and at least one user ID, email or username")
+	}
+
+	platform := args[0]
+	users := args[1:]
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancelCtx()
+
+	for _, u := range users {
+		var (
+			res *restapi.User
+			err error
+		)
+
+		switch _, atoiErr := strconv.Atoi(u); {
+		case atoiErr == nil:
+			res, err = client.User(ctx, platform, u)
+		case strings.Contains(u, "@"):
+			res, err = client.UserByEmail(ctx, platform, u)
+		default:
+			res, err = client.UserByUsername(ctx, platform, u)
+		}
+
+		if err != nil {
+			die("error fetching user (%s): %v", u, err)
+		}
+
+		json.NewEncoder(os.Stdout).Encode(res)
+	}
+}
+
+func cmdVideoFiles(client *restapi.Client, args []string) {
+	fs := flag.NewFlagSet("video-files", flag.ExitOnError)
+
+	fFormat := fs.String("format", "json", "Output format, json or table")
+	fMediaFormat := fs.String("media-format", "", "Only include video files of the given media format")
+
+	ids := parseFlags(fs, args)
+
+	if len(ids) < 1 {
 		die("need at least one asset ID")
 	}
 
-	ids := args
+	if *fFormat != "json" && *fFormat != "table" {
+		die("unknown format %q", *fFormat)
+	}
 
 	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancelCtx()
@@ -181,6 +792,199 @@ func cmdVideoFiles(client *restapi.Client, args []string) {
 			die("error fetching video file data (%s): %v", id, err)
 		}
 
+		if *fMediaFormat != "" {
+			res = res.Filter(*fMediaFormat)
+		}
+
+		if *fFormat == "table" {
+			printVideofilesTable(res)
+			continue
+		}
+
 		json.NewEncoder(os.Stdout).Encode(res)
 	}
 }
+
+// printVideofilesTable prints the bitrate ladder of the video files, with
+// gaps where a rendition has more than twice the bitrate of the one below.
+func printVideofilesTable(res *restapi.VideofilesResponse) {
+	fmt.Printf("%d %s\n", res.AssetID, res.Title)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "BITRATE\tFORMAT\tSIZE\tURL")
+
+	for _, vf := range res.SortedByBitrate() {
+		fmt.Fprintf(w, "%d\t%s\t%.1f MB\t%s\n", vf.Bitrate, vf.MediaFormat, float64(vf.FileSize)/(1<<20), vf.PlayURL())
+	}
+
+	w.Flush()
+
+	for _, gap := range res.LadderGaps(2) {
+		fmt.Printf("gap: %d -> %d (%.1fx)\n", gap.From, gap.To, gap.Ratio)
+	}
+
+	fmt.Println()
+}
+
+func cmdVouchers(client *restapi.Client, args []string) {
+	if len(args) < 2 {
+		die("need voucher action and platform")
+	}
+
+	action := args[0]
+	platform := args[1]
+	args = args[2:]
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancelCtx()
+
+	switch action {
+	case "show":
+		if len(args) < 1 {
+			die("need at least one voucher code")
+		}
+
+		for _, code := range args {
+			res, err := client.Voucher(ctx, platform, code)
+			if err != nil {
+				die("error fetching voucher (%s): %v", code, err)
+			}
+
+			json.NewEncoder(os.Stdout).Encode(res)
+		}
+	case "validate":
+		if len(args) < 2 {
+			die("need product payment ID and at least one voucher code")
+		}
+
+		productPaymentID := args[0]
+
+		for _, code := range args[1:] {
+			status := "ok"
+
+			if err := client.ValidateVoucher(ctx, platform, code, productPaymentID); err != nil {
+				status = err.Error()
+			}
+
+			fmt.Printf("%s\t%s\n", code, status)
+		}
+	case "generate":
+		fs := flag.NewFlagSet("vouchers generate", flag.ExitOnError)
+
+		fProductPayment := fs.String("product-payment", "", "Product payment ID")
+		fCount := fs.Int("count", 0, "Number of vouchers to generate")
+		fCampaign := fs.String("campaign", "", "Campaign name")
+		fPrefix := fs.String("prefix", "", "Voucher code prefix")
+		fStarts := fs.String("starts", "", "Start time of the vouchers (RFC 3339)")
+		fExpires := fs.String("expires", "", "Expiry time of the vouchers (RFC 3339)")
+		fMaxUsages := fs.Int("max-usages", 0, "Maximum number of usages per voucher")
+
+		fs.Parse(args)
+
+		if *fProductPayment == "" || *fCount < 1 {
+			die("need product payment ID and a positive count")
+		}
+
+		vouchers, err := client.GenerateVouchers(ctx, platform, restapi.VoucherBatch{
+			ProductPaymentID: *fProductPayment,
+			Campaign:         *fCampaign,
+			Count:            *fCount,
+			Prefix:           *fPrefix,
+			StartDate:        parseTimeFlag("starts", *fStarts),
+			ExpiryDate:       parseTimeFlag("expires", *fExpires),
+			MaxUsages:        *fMaxUsages,
+		})
+		if err != nil {
+			die("error generating vouchers: %v", err)
+		}
+
+		formatTime := func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+
+			return t.Format(time.RFC3339)
+		}
+
+		w := csv.NewWriter(os.Stdout)
+
+		w.Write([]string{"code", "product_payment_id", "campaign", "start_date", "expiry_date", "max_usages"})
+
+		for _, v := range vouchers {
+			w.Write([]string{
+				v.Code,
+				v.ProductPaymentID,
+				v.Campaign,
+				formatTime(v.StartDate),
+				formatTime(v.ExpiryDate),
+				strconv.Itoa(v.MaxUsages),
+			})
+		}
+
+		w.Flush()
+
+		if err := w.Error(); err != nil {
+			die("error writing vouchers: %v", err)
+		}
+	default:
+		die("unknown voucher action %q", action)
+	}
+}
+
+func parseTimeFlag(name, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		die("error parsing %s flag: %v", name, err)
+	}
+
+	return t
+}
+
+func parseBoolFlag(name, value string) *bool {
+	if value == "" {
+		return nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		die("error parsing %s flag: %v", name, err)
+	}
+
+	return &b
+}
+
+// parseFlags parses the flags in args, allowing them to be interspersed with
+// positional arguments, and returns the positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+
+	for {
+		fs.Parse(args)
+
+		if fs.NArg() == 0 {
+			return positional
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// confirm asks the user the given yes or no question on stderr.
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
@@ -1,11 +1,13 @@
 package restapi
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"maps"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -13,7 +15,10 @@ import (
 )
 
 // Asset returns an asset from the Vimond Rest API
-func (c *Client) Asset(ctx context.Context, platform, assetID string) (*Asset, error) {
+func (c *Client) Asset(ctx context.Context, platform, assetID string) (_ *Asset, err error) {
+	ctx, op := c.startOperation(ctx, "Asset", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
 	if _, err := strconv.Atoi(assetID); err != nil {
 		return nil, ErrInvalidAssetID
 	}
@@ -27,13 +32,8 @@ func (c *Client) Asset(ctx context.Context, platform, assetID string) (*Asset, e
 		resp.Body.Close()
 	}()
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		break
-	case http.StatusNotFound:
-		return nil, ErrNotFound
-	default:
-		return nil, ErrUnknown
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
 	}
 
 	return parseAsset(resp.Body)
@@ -45,7 +45,10 @@ func (c *Client) Asset(ctx context.Context, platform, assetID string) (*Asset, e
 // application/json; v=2; charset=utf-8
 // application/json; charset=utf-8
 // application/xml; charset=utf-8
-func (c *Client) AssetRaw(ctx context.Context, platform, assetID, headerAccept string) ([]byte, error) {
+func (c *Client) AssetRaw(ctx context.Context, platform, assetID, headerAccept string) (_ []byte, err error) {
+	ctx, op := c.startOperation(ctx, "AssetRaw", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
 	if headerAccept != "" {
 		c.headerAccept = headerAccept
 	}
@@ -57,7 +60,7 @@ func (c *Client) AssetRaw(ctx context.Context, platform, assetID, headerAccept s
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Vimond replies with status %d", resp.StatusCode)
+		return nil, newAPIError(resp)
 	}
 
 	b, err := ioutil.ReadAll(resp.Body)
@@ -68,6 +71,162 @@ func (c *Client) AssetRaw(ctx context.Context, platform, assetID, headerAccept s
 	return b, nil
 }
 
+// CreateAsset creates an asset from the given asset. The ID of the given asset
+// is ignored.
+func (c *Client) CreateAsset(ctx context.Context, platform string, asset *Asset) (_ *Asset, err error) {
+	if asset == nil {
+		return nil, fmt.Errorf("vimond/restapi: nil asset")
+	}
+
+	ctx, op := c.startOperation(ctx, "CreateAsset", platform)
+	defer op.end(&err)
+
+	a := *asset
+	a.ID = ""
+
+	body, err := marshalAsset(&a)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.post(ctx, fmt.Sprintf("/api/%s/asset", platform), url.Values{}, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, newAPIError(resp)
+	}
+
+	return parseAsset(resp.Body)
+}
+
+// UpdateAsset replaces the asset with the given asset, identified by its ID.
+func (c *Client) UpdateAsset(ctx context.Context, platform string, asset *Asset) (_ *Asset, err error) {
+	if asset == nil {
+		return nil, fmt.Errorf("vimond/restapi: nil asset")
+	}
+
+	ctx, op := c.startOperation(ctx, "UpdateAsset", platform, AttributeAssetID.String(asset.ID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(asset.ID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	body, err := marshalAsset(asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.putAsset(ctx, platform, asset.ID, body)
+}
+
+// UpdateAssetFields updates an asset by overwriting the given field values,
+// keyed by their Vimond JSON names. This method fetches the given asset,
+// strips null values and nested objects, sets the values, and PUTs the
+// resulting object back. The category, metadata and imageVersions of the
+// asset are nested objects, so they are not sent back in the PUT.
+func (c *Client) UpdateAssetFields(ctx context.Context, platform, assetID string, values map[string]interface{}) (_ *Asset, err error) {
+	ctx, op := c.startOperation(ctx, "UpdateAssetFields", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	resp, err := c.get(ctx, c.assetPath(platform, assetID), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var rawAsset map[string]interface{}
+
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+
+	if err := dec.Decode(&rawAsset); err != nil {
+		return nil, err
+	}
+
+	// The Vimond API seems to explode on null values and nested objects, so
+	// remove these.
+	for k, v := range rawAsset {
+		if v == nil {
+			delete(rawAsset, k)
+			continue
+		}
+		if _, ok := v.(map[string]interface{}); ok {
+			delete(rawAsset, k)
+		}
+	}
+
+	for k, v := range values {
+		rawAsset[k] = v
+	}
+
+	body, err := json.Marshal(&rawAsset)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.putAsset(ctx, platform, assetID, body)
+}
+
+// DeleteAsset deletes an asset.
+func (c *Client) DeleteAsset(ctx context.Context, platform, assetID string) (err error) {
+	ctx, op := c.startOperation(ctx, "DeleteAsset", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return ErrInvalidAssetID
+	}
+
+	resp, err := c.delete(ctx, c.assetPath(platform, assetID), url.Values{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+func (c *Client) putAsset(ctx context.Context, platform, assetID string, body []byte) (*Asset, error) {
+	resp, err := c.put(ctx, c.assetPath(platform, assetID), url.Values{}, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseAsset(resp.Body)
+}
+
 func (c *Client) assetPath(platform, assetID string) string {
 	return fmt.Sprintf("/api/%s/asset/%s", platform, assetID)
 }
@@ -85,6 +244,8 @@ func parseAsset(r io.Reader) (*Asset, error) {
 		*Alias
 	}
 
+	asset.Alias = &Alias{}
+
 	if err := json.NewDecoder(r).Decode(&asset); err != nil {
 		return nil, err
 	}
@@ -98,6 +259,77 @@ func parseAsset(r io.Reader) (*Asset, error) {
 	return (*Asset)(asset.Alias), nil
 }
 
+// marshalAsset encodes an asset in the format expected by the Vimond Rest
+// API, which is the inverse of parseAsset. Empty IDs and zero times are left
+// out, as is the category, which is set using the category ID.
+func marshalAsset(a *Asset) ([]byte, error) {
+	type Alias Asset
+
+	id := func(name, s string) (*int, error) {
+		if s == "" {
+			return nil, nil
+		}
+
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("vimond/restapi: invalid %s %q", name, s)
+		}
+
+		return &n, nil
+	}
+
+	date := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+
+		return &t
+	}
+
+	var (
+		asset struct {
+			AssetTypeID *int `json:"assetTypeId,omitempty"`
+			CategoryID  *int `json:"categoryId,omitempty"`
+			ChannelID   *int `json:"channelId,omitempty"`
+			ID          *int `json:"id,omitempty"`
+
+			CreateTime        *time.Time `json:"createTime,omitempty"`
+			ExpireDate        *time.Time `json:"expireDate,omitempty"`
+			LiveBroadcastTime *time.Time `json:"liveBroadcastTime,omitempty"`
+			UpdateTime        *time.Time `json:"updateTime,omitempty"`
+
+			Category *Category `json:"category,omitempty"`
+
+			*Alias
+		}
+		err error
+	)
+
+	if asset.AssetTypeID, err = id("asset type id", a.AssetTypeID); err != nil {
+		return nil, err
+	}
+
+	if asset.CategoryID, err = id("category id", a.CategoryID); err != nil {
+		return nil, err
+	}
+
+	if asset.ChannelID, err = id("channel id", a.ChannelID); err != nil {
+		return nil, err
+	}
+
+	if asset.ID, err = id("asset id", a.ID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	asset.CreateTime = date(a.CreateTime)
+	asset.ExpireDate = date(a.ExpireDate)
+	asset.LiveBroadcastTime = date(a.LiveBroadcastTime)
+	asset.UpdateTime = date(a.UpdateTime)
+	asset.Alias = (*Alias)(a)
+
+	return json.Marshal(&asset)
+}
+
 // Asset is a Vimond Rest API asset
 type Asset struct {
 	ID         string `json:"id"`
@@ -179,9 +411,67 @@ func (c *Category) In(id string) bool {
 	return false
 }
 
-// AssetMetadata represents Vimond asset metadata.
+// AssetMetadata represents Vimond asset metadata. Entries holds the entries
+// known by this package, and All holds every entry, including the known ones.
+//
+// When encoded, the known fields set in Entries take precedence over the
+// same entries in All.
 type AssetMetadata struct {
 	Entries MetadataEntries `json:"entries"`
+	All     MetadataMap     `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, filling in both Entries and All.
+// All is nil for metadata without entries.
+func (am *AssetMetadata) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Entries json.RawMessage `json:"entries"`
+	}
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	*am = AssetMetadata{}
+
+	if len(v.Entries) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(v.Entries, &am.Entries); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(v.Entries, &am.All); err != nil {
+		return err
+	}
+
+	if len(am.All) == 0 {
+		am.All = nil
+	}
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the entries in All merged
+// with the fields set in Entries.
+func (am AssetMetadata) MarshalJSON() ([]byte, error) {
+	entries := MetadataMap{}
+
+	maps.Copy(entries, am.All)
+
+	b, err := json.Marshal(am.Entries)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		Entries MetadataMap `json:"entries"`
+	}{entries})
 }
 
 // MetadataEntries is metadata for an Asset in the Vimond Rest API
@@ -210,27 +500,3 @@ type MetadataEntries struct {
 	Title                  LocalizedField `json:"title,omitempty"`
 	YouTubeTemplate        LocalizedField `json:"youtube-template,omitempty"`
 }
-
-// LocalizedField is field with localized values
-type LocalizedField []LocalizedValue
-
-// Value returns the value for the given lang, fallback to *
-func (lf LocalizedField) Value(lang string) string {
-	def := LocalizedValue{}
-
-	for _, l := range lf {
-		if l.Lang == lang {
-			return l.Value
-		} else if l.Lang == "*" {
-			def = l
-		}
-	}
-
-	return def.Value
-}
-
-// LocalizedValue is a representation of a multi-language value
-type LocalizedValue struct {
-	Lang  string `json:"lang"`
-	Value string `json:"value"`
-}
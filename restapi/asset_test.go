@@ -1,13 +1,18 @@
 package restapi
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -73,6 +78,11 @@ func TestAsset(t *testing.T) {
 								{Value: "louise press title bar", Lang: "en_US"},
 							},
 						},
+						All: MetadataMap{
+							MetadataLouisePressTitle: []LocalizedValue{
+								{Value: "louise press title bar", Lang: "en_US"},
+							},
+						},
 					},
 				},
 			},
@@ -162,6 +172,226 @@ func TestAssetRaw(t *testing.T) {
 	})
 }
 
+func TestCreateAsset(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Method, http.MethodPost; got != want {
+			t.Errorf("r.Method = %q, want %q", got, want)
+		}
+
+		if got, want := r.URL.Path, "/api/tv4/asset"; got != want {
+			t.Errorf("r.URL.Path = %q, want %q", got, want)
+		}
+
+		var body map[string]interface{}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, ok := body["id"]; ok {
+			t.Errorf("body has id %v", body["id"])
+		}
+
+		if got, want := body["categoryId"], float64(10002); got != want {
+			t.Errorf(`body["categoryId"] = %v, want %v`, got, want)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":10006,"categoryId":10002,"title":"title foo"}`))
+	})
+	defer ts.Close()
+
+	asset, err := c.CreateAsset(context.Background(), "tv4", &Asset{ID: "123", CategoryID: "10002", Title: "title foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := asset.ID, "10006"; got != want {
+		t.Errorf("asset.ID = %q, want %q", got, want)
+	}
+
+	if _, err := c.CreateAsset(context.Background(), "tv4", nil); err == nil {
+		t.Errorf("expected error for nil asset")
+	}
+}
+
+func TestUpdateAsset(t *testing.T) {
+	t.Run("InvalidAssetID", func(t *testing.T) {
+		c := &Client{}
+
+		if _, err := c.UpdateAsset(context.Background(), "tv4", &Asset{}); err != ErrInvalidAssetID {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("NilAsset", func(t *testing.T) {
+		c := &Client{}
+
+		if _, err := c.UpdateAsset(context.Background(), "tv4", nil); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			if got, want := r.Method, http.MethodPut; got != want {
+				t.Errorf("r.Method = %q, want %q", got, want)
+			}
+
+			if got, want := r.URL.Path, "/api/tv4/asset/10006"; got != want {
+				t.Errorf("r.URL.Path = %q, want %q", got, want)
+			}
+
+			io.Copy(w, r.Body)
+		})
+		defer ts.Close()
+
+		asset, err := c.UpdateAsset(context.Background(), "tv4", &Asset{ID: "10006", Title: "title foo"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := asset.Title, "title foo"; got != want {
+			t.Errorf("asset.Title = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestUpdateAssetFields(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"id":10006,"title":"title foo","views":9007199254740993,"description":null,"imageVersions":{"images":[]}}`))
+		case http.MethodPut:
+			b, _ := ioutil.ReadAll(r.Body)
+
+			if got, want := string(b), `{"id":10006,"title":"title bar","views":9007199254740993}`; got != want {
+				t.Errorf("body = %s, want %s", got, want)
+			}
+
+			w.Write(b)
+		}
+	})
+	defer ts.Close()
+
+	asset, err := c.UpdateAssetFields(context.Background(), "tv4", "10006", map[string]interface{}{"title": "title bar"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := asset.Title, "title bar"; got != want {
+		t.Errorf("asset.Title = %q, want %q", got, want)
+	}
+}
+
+func TestDeleteAsset(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			if got, want := r.Method, http.MethodDelete; got != want {
+				t.Errorf("r.Method = %q, want %q", got, want)
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+		defer ts.Close()
+
+		if err := c.DeleteAsset(context.Background(), "tv4", "10006"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		defer ts.Close()
+
+		if err := c.DeleteAsset(context.Background(), "tv4", "10006"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("err = %v, want %v", err, ErrNotFound)
+		}
+	})
+
+	t.Run("NotRetried", func(t *testing.T) {
+		var attempts int
+
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}, Retry(RetryPolicy{MaxAttempts: 3, MaxBackoff: time.Millisecond}))
+		defer ts.Close()
+
+		if err := c.DeleteAsset(context.Background(), "tv4", "10006"); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		if got, want := attempts, 1; got != want {
+			t.Errorf("attempts = %d, want %d", got, want)
+		}
+	})
+}
+
+func TestMarshalAsset(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		in := &Asset{
+			ID:          "10006",
+			AssetTypeID: "10001",
+			CategoryID:  "10002",
+			ChannelID:   "10003",
+			Title:       "title foo",
+			Duration:    10004,
+			Live:        true,
+			CreateTime:  time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC),
+		}
+
+		b, err := marshalAsset(in)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		out, err := parseAsset(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !cmp.Equal(in, out) {
+			t.Errorf("round trip mismatch (-in +out):\n%s", cmp.Diff(in, out))
+		}
+	})
+
+	t.Run("WireFormat", func(t *testing.T) {
+		b, err := marshalAsset(&Asset{ID: "10006", Title: "title foo"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var m map[string]interface{}
+
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := m["id"], float64(10006); got != want {
+			t.Errorf(`m["id"] = %v, want %v`, got, want)
+		}
+
+		for _, k := range []string{"categoryId", "createTime", "expireDate", "category"} {
+			if v, ok := m[k]; ok {
+				t.Errorf("m[%q] = %v, want it left out", k, v)
+			}
+		}
+	})
+
+	t.Run("InvalidID", func(t *testing.T) {
+		if _, err := marshalAsset(&Asset{ID: "foo"}); err != ErrInvalidAssetID {
+			t.Fatalf("err = %v, want %v", err, ErrInvalidAssetID)
+		}
+
+		if _, err := marshalAsset(&Asset{CategoryID: "foo"}); err == nil {
+			t.Fatal("err is nil")
+		}
+	})
+}
+
 func TestImageVersions(t *testing.T) {
 	t.Run("TypeURL", func(t *testing.T) {
 		for _, tt := range []struct {
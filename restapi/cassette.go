@@ -0,0 +1,257 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const redacted = "REDACTED"
+
+// redactedHeaders are recorded as REDACTED, since they carry credentials.
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// Record makes the client record all requests and responses to a cassette
+// file at path, which is overwritten. The Authorization header, cookies and
+// any occurrences of the api key and secret are redacted. The file is
+// rewritten after every request, and errors writing it are returned by the
+// request.
+func Record(path string) func(*Client) {
+	return func(c *Client) {
+		c.cassette = &cassette{path: path, record: true}
+	}
+}
+
+// Replay makes the client respond to requests from a cassette file recorded
+// with Record, without sending them. Requests are matched on method, path
+// and query, ignoring headers such as Date and Authorization, which depend
+// on the time of the request. Recorded responses are used once each, in the
+// order they were recorded. Requests without a recorded response, as well as
+// any error reading the cassette, are returned as errors.
+func Replay(path string) func(*Client) {
+	return func(c *Client) {
+		cas := &cassette{path: path}
+
+		b, err := ioutil.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(b, &cas.file)
+		}
+
+		if err != nil {
+			cas.err = fmt.Errorf("vimond/restapi: reading cassette: %w", err)
+		}
+
+		cas.used = make([]bool, len(cas.file.Interactions))
+
+		c.cassette = cas
+	}
+}
+
+type cassette struct {
+	path   string
+	record bool
+	err    error
+
+	mu   sync.Mutex
+	file cassetteFile
+	used []bool
+}
+
+type cassetteFile struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string       `json:"method"`
+	Path   string       `json:"path"`
+	Query  string       `json:"query,omitempty"`
+	Header http.Header  `json:"header,omitempty"`
+	Body   recordedBody `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	StatusCode int          `json:"statusCode"`
+	Header     http.Header  `json:"header,omitempty"`
+	Body       recordedBody `json:"body,omitempty"`
+}
+
+// recordedBody is encoded as a string if it is valid UTF-8, which keeps
+// cassettes readable, and as {"base64": "..."} otherwise.
+type recordedBody []byte
+
+func (rb recordedBody) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(rb) {
+		return json.Marshal(string(rb))
+	}
+
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(rb)})
+}
+
+func (rb *recordedBody) UnmarshalJSON(data []byte) error {
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		*rb = recordedBody(s)
+		return nil
+	}
+
+	var b struct {
+		Base64 []byte `json:"base64"`
+	}
+
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+
+	*rb = b.Base64
+
+	return nil
+}
+
+func (cas *cassette) roundTrip(c *Client, req *http.Request) (*http.Response, error) {
+	if cas.err != nil {
+		return nil, cas.err
+	}
+
+	if cas.record {
+		return cas.recordRoundTrip(c, req)
+	}
+
+	return cas.replay(c, req)
+}
+
+func (cas *cassette) replay(c *Client, req *http.Request) (*http.Response, error) {
+	cas.mu.Lock()
+	defer cas.mu.Unlock()
+
+	// The query is redacted the same way as when it was recorded.
+	query := strings.NewReplacer(redactions(c.apiKey, c.secret)...).Replace(req.URL.Query().Encode())
+
+	for i, in := range cas.file.Interactions {
+		if cas.used[i] || in.Request.Method != req.Method || in.Request.Path != req.URL.Path || in.Request.Query != query {
+			continue
+		}
+
+		cas.used[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("vimond/restapi: no recorded response for %s %s", req.Method, req.URL.RequestURI())
+}
+
+func (cas *cassette) recordRoundTrip(c *Client, req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		reqBody = b
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	r := strings.NewReplacer(redactions(c.apiKey, c.secret)...)
+
+	in := interaction{
+		Request: recordedRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  r.Replace(req.URL.Query().Encode()),
+			Header: redactHeader(req.Header, r),
+			Body:   recordedBody(r.Replace(string(reqBody))),
+		},
+		Response: recordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header, r),
+			Body:       recordedBody(r.Replace(string(respBody))),
+		},
+	}
+
+	cas.mu.Lock()
+	defer cas.mu.Unlock()
+
+	cas.file.Interactions = append(cas.file.Interactions, in)
+
+	b, err := json.MarshalIndent(&cas.file, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ioutil.WriteFile(cas.path, append(b, '\n'), 0644); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("vimond/restapi: writing cassette: %w", err)
+	}
+
+	return resp, nil
+}
+
+// redactions returns the replacer pairs for the non-empty secrets.
+func redactions(secrets ...string) []string {
+	var pairs []string
+
+	for _, s := range secrets {
+		if s != "" {
+			pairs = append(pairs, s, redacted)
+		}
+	}
+
+	return pairs
+}
+
+func redactHeader(h http.Header, r *strings.Replacer) http.Header {
+	rh := make(http.Header, len(h))
+
+	for name, values := range h {
+		for _, v := range values {
+			rh.Add(name, r.Replace(v))
+		}
+	}
+
+	for _, name := range redactedHeaders {
+		if _, ok := rh[name]; ok {
+			rh.Set(name, redacted)
+		}
+	}
+
+	return rh
+}
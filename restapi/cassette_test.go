@@ -0,0 +1,92 @@
+package restapi
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/admin/platforms":
+			w.Write([]byte(`[{"id":1,"name":"tv4"}]`))
+		case "/api/tv4/asset/123":
+			w.Header().Set("Set-Cookie", "session=abc")
+			w.Write([]byte(`{"id":123,"title":"Nyheterna"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}, Credentials("key123", "secret456"), Record(path))
+
+	ctx := context.Background()
+
+	if _, err := c.Platforms(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a, err := c.Asset(ctx, "tv4", "123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := a.Title, "Nyheterna"; got != want {
+		t.Errorf("a.Title = %q, want %q", got, want)
+	}
+
+	ts.Close()
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, secret := range []string{"key123", "secret456", "SUMO", "session=abc"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, b)
+		}
+	}
+
+	t.Run("Replay", func(t *testing.T) {
+		c := NewClient(BaseURL("http://example.invalid"), Credentials("other", "other"), Replay(path))
+
+		a, err := c.Asset(ctx, "tv4", "123")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := a.Title, "Nyheterna"; got != want {
+			t.Errorf("a.Title = %q, want %q", got, want)
+		}
+
+		platforms, err := c.Platforms(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := len(platforms), 1; got != want {
+			t.Fatalf("len(platforms) = %d, want %d", got, want)
+		}
+
+		if _, err := c.Platforms(ctx); err == nil {
+			t.Errorf("expected error for request without a recorded response")
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		c := NewClient(Replay(filepath.Join(t.TempDir(), "missing.json")))
+
+		_, err := c.Platforms(ctx)
+
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("err = %v, want a not exist error", err)
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Category returns a category. Only the ID of the parent category is set.
+func (c *Client) Category(ctx context.Context, platform, categoryID string) (_ *Category, err error) {
+	ctx, op := c.startOperation(ctx, "Category", platform)
+	defer op.end(&err)
+
+	resp, err := c.get(ctx, c.categoryPath(platform, categoryID), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseCategory(resp.Body)
+}
+
+// CategoryChildren returns the direct sub categories of a category.
+func (c *Client) CategoryChildren(ctx context.Context, platform, categoryID string) (_ []*Category, err error) {
+	ctx, op := c.startOperation(ctx, "CategoryChildren", platform)
+	defer op.end(&err)
+
+	resp, err := c.get(ctx, c.categoryPath(platform, categoryID)+"/categories", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseCategories(resp.Body)
+}
+
+// CategoryTree returns the category tree below the given category, using
+// "root" for the whole tree of the platform. The tree is expanded depth
+// levels below the category, or completely if depth is negative.
+func (c *Client) CategoryTree(ctx context.Context, platform, categoryID string, depth int) (_ *CategoryTree, err error) {
+	ctx, op := c.startOperation(ctx, "CategoryTree", platform)
+	defer op.end(&err)
+
+	root, err := c.Category(ctx, platform, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &CategoryNode{Category: *root}
+
+	if err := c.expandCategory(ctx, platform, node, depth); err != nil {
+		return nil, err
+	}
+
+	return &CategoryTree{Root: node}, nil
+}
+
+func (c *Client) expandCategory(ctx context.Context, platform string, node *CategoryNode, depth int) error {
+	if depth == 0 {
+		return nil
+	}
+
+	children, err := c.CategoryChildren(ctx, platform, node.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		child.Parent = &node.Category
+
+		n := &CategoryNode{Category: *child}
+
+		if err := c.expandCategory(ctx, platform, n, depth-1); err != nil {
+			return err
+		}
+
+		node.Children = append(node.Children, n)
+	}
+
+	return nil
+}
+
+// CategoryAssets returns an iterator over the assets in a category. Pages of
+// pageSize assets are fetched lazily as the iterator is consumed, and
+// iteration stops after the first error.
+func (c *Client) CategoryAssets(ctx context.Context, platform, categoryID string, pageSize int) iter.Seq2[*Asset, error] {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	return paginate(pageSize, func(start int) (_ []*Asset, _ int, err error) {
+		ctx, op := c.startOperation(ctx, "CategoryAssets", platform)
+		defer op.end(&err)
+
+		query := url.Values{
+			"expand": {"metadata,category"},
+			"start":  {strconv.Itoa(start)},
+			"size":   {strconv.Itoa(pageSize)},
+		}
+
+		resp, err := c.get(ctx, c.categoryPath(platform, categoryID)+"/assets", query)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer func() {
+			io.CopyN(ioutil.Discard, resp.Body, 64)
+			resp.Body.Close()
+		}()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, 0, newAPIError(resp)
+		}
+
+		return parseAssetSearch(resp.Body)
+	})
+}
+
+func (c *Client) categoryPath(platform, categoryID string) string {
+	return fmt.Sprintf("/api/%s/category/%s", platform, categoryID)
+}
+
+// CategoryTree is a tree of categories
+type CategoryTree struct {
+	Root *CategoryNode
+}
+
+// CategoryNode is a category in a CategoryTree. The Parent of the embedded
+// Category points to the category of the parent node.
+type CategoryNode struct {
+	Category
+
+	Children []*CategoryNode
+}
+
+// Walk calls fn for every node in the tree, depth first, with the depth of the
+// node relative to the root. The children of a node are skipped if fn returns
+// false.
+func (t *CategoryTree) Walk(fn func(n *CategoryNode, depth int) bool) {
+	if t == nil || t.Root == nil {
+		return
+	}
+
+	t.Root.walk(fn, 0)
+}
+
+func (n *CategoryNode) walk(fn func(*CategoryNode, int) bool, depth int) {
+	if !fn(n, depth) {
+		return
+	}
+
+	for _, child := range n.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// Find returns the node with the given category ID, or nil if there is none.
+func (t *CategoryTree) Find(id string) *CategoryNode {
+	var found *CategoryNode
+
+	t.Walk(func(n *CategoryNode, _ int) bool {
+		if n.ID == id {
+			found = n
+		}
+
+		return found == nil
+	})
+
+	return found
+}
+
+// Path returns the categories from the root of the tree down to the category
+// with the given ID, or nil if it is not in the tree.
+func (t *CategoryTree) Path(id string) []*Category {
+	n := t.Find(id)
+	if n == nil {
+		return nil
+	}
+
+	var path []*Category
+
+	for c := &n.Category; c != nil; c = c.Parent {
+		path = append([]*Category{c}, path...)
+
+		if c == &t.Root.Category {
+			break
+		}
+	}
+
+	return path
+}
+
+type vimondCategory struct {
+	ID       json.Number `json:"id"`
+	ParentID json.Number `json:"parentId"`
+	Title    string      `json:"title"`
+}
+
+func (vc vimondCategory) category() *Category {
+	c := &Category{
+		ID:    vc.ID.String(),
+		Title: vc.Title,
+	}
+
+	if vc.ParentID != "" {
+		c.Parent = &Category{ID: vc.ParentID.String()}
+	}
+
+	return c
+}
+
+func parseCategory(r io.Reader) (*Category, error) {
+	var vc vimondCategory
+
+	if err := json.NewDecoder(r).Decode(&vc); err != nil {
+		return nil, err
+	}
+
+	return vc.category(), nil
+}
+
+func parseCategories(r io.Reader) ([]*Category, error) {
+	var resp []vimondCategory
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	categories := make([]*Category, 0, len(resp))
+
+	for n := range resp {
+		categories = append(categories, resp[n].category())
+	}
+
+	return categories, nil
+}
@@ -0,0 +1,172 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCategory(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/api/tv4/category/123"; got != want {
+			t.Errorf("r.URL.Path = %q, want %q", got, want)
+		}
+
+		w.Write([]byte(`{"id":123,"parentId":"12","title":"foo"}`))
+	})
+	defer ts.Close()
+
+	category, err := c.Category(context.Background(), "tv4", "123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := category.ID, "123"; got != want {
+		t.Errorf("category.ID = %q, want %q", got, want)
+	}
+
+	if got, want := category.Title, "foo"; got != want {
+		t.Errorf("category.Title = %q, want %q", got, want)
+	}
+
+	if !category.In("12") {
+		t.Errorf("category is not in its parent")
+	}
+}
+
+func TestCategoryTree(t *testing.T) {
+	children := map[string]string{
+		"100": `[{"id":1,"title":"foo"},{"id":2,"title":"bar"}]`,
+		"1":   `[{"id":11,"title":"foo-foo"}]`,
+		"2":   `[]`,
+		"11":  `[{"id":111,"title":"foo-foo-foo"}]`,
+		"111": `[]`,
+	}
+
+	var requests int
+
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		id := strings.TrimPrefix(r.URL.Path, "/api/tv4/category/")
+
+		if id == "root" {
+			w.Write([]byte(`{"id":100,"title":"Root"}`))
+			return
+		}
+
+		w.Write([]byte(children[strings.TrimSuffix(id, "/categories")]))
+	})
+	defer ts.Close()
+
+	t.Run("Complete", func(t *testing.T) {
+		tree, err := c.CategoryTree(context.Background(), "tv4", "root", -1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var walked []string
+
+		tree.Walk(func(n *CategoryNode, depth int) bool {
+			walked = append(walked, fmt.Sprintf("%d:%s", depth, n.ID))
+			return true
+		})
+
+		if got, want := strings.Join(walked, " "), "0:100 1:1 2:11 3:111 1:2"; got != want {
+			t.Errorf("walked = %q, want %q", got, want)
+		}
+
+		if got := tree.Find("3"); got != nil {
+			t.Errorf(`tree.Find("3") = %+v, want nil`, got)
+		}
+
+		if got, want := tree.Find("11").Title, "foo-foo"; got != want {
+			t.Errorf(`tree.Find("11").Title = %q, want %q`, got, want)
+		}
+
+		var path []string
+
+		for _, c := range tree.Path("111") {
+			path = append(path, c.ID)
+		}
+
+		if got, want := strings.Join(path, "/"), "100/1/11/111"; got != want {
+			t.Errorf(`tree.Path("111") = %q, want %q`, got, want)
+		}
+
+		if !tree.Find("111").In("1") {
+			t.Errorf(`tree.Find("111").In("1") = false, want true`)
+		}
+	})
+
+	t.Run("DepthLimited", func(t *testing.T) {
+		requests = 0
+
+		tree, err := c.CategoryTree(context.Background(), "tv4", "root", 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := requests, 2; got != want {
+			t.Errorf("requests = %d, want %d", got, want)
+		}
+
+		if got, want := len(tree.Root.Children), 2; got != want {
+			t.Fatalf("len(tree.Root.Children) = %d, want %d", got, want)
+		}
+
+		if got := tree.Find("11"); got != nil {
+			t.Errorf(`tree.Find("11") = %+v, want nil`, got)
+		}
+	})
+
+	t.Run("SkipChildren", func(t *testing.T) {
+		tree, err := c.CategoryTree(context.Background(), "tv4", "root", -1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var n int
+
+		tree.Walk(func(node *CategoryNode, depth int) bool {
+			n++
+			return depth < 1
+		})
+
+		if got, want := n, 3; got != want {
+			t.Errorf("walked %d nodes, want %d", got, want)
+		}
+	})
+}
+
+func TestCategoryAssets(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/api/tv4/category/123/assets"; got != want {
+			t.Errorf("r.URL.Path = %q, want %q", got, want)
+		}
+
+		switch r.URL.Query().Get("start") {
+		case "0":
+			w.Write([]byte(`{"assets":{"asset":[{"id":1},{"id":2}]}}`))
+		default:
+			w.Write([]byte(`{"assets":{"asset":[{"id":3}]}}`))
+		}
+	})
+	defer ts.Close()
+
+	var ids []string
+
+	for asset, err := range c.CategoryAssets(context.Background(), "tv4", "123", 2) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ids = append(ids, asset.ID)
+	}
+
+	if got, want := strings.Join(ids, ","), "1,2,3"; got != want {
+		t.Errorf("ids = %q, want %q", got, want)
+	}
+}
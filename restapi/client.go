@@ -6,19 +6,31 @@ Package restapi contains a client for the Vimond REST API
 package restapi
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"maps"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"slices"
 	"time"
 )
 
 // Errors
 var (
-	ErrInvalidAssetID = errors.New("vimond/restapi: invalid asset id")
-	ErrNotFound       = errors.New("vimond/restapi: not found")
-	ErrUnknown        = errors.New("vimond/restapi: unknown")
+	ErrInvalidAssetID  = errors.New("vimond/restapi: invalid asset id")
+	ErrInvalidSubtitle = errors.New("vimond/restapi: invalid subtitle")
+	ErrMissingMetadata = errors.New("vimond/restapi: missing metadata")
+	ErrNotFound        = errors.New("vimond/restapi: not found")
+	ErrUnknown         = errors.New("vimond/restapi: unknown")
+	ErrVoucherExpired  = errors.New("vimond/restapi: voucher expired")
+	ErrVoucherInvalid  = errors.New("vimond/restapi: voucher invalid")
+	ErrVoucherUsed     = errors.New("vimond/restapi: voucher used")
 )
 
 const (
@@ -37,6 +49,13 @@ type Client struct {
 	secret       string
 	userAgent    string
 	headerAccept string
+	retryPolicy  *RetryPolicy
+	rateLimiter  *rateLimiter
+	cassette     *cassette
+	middleware   []Middleware
+	telemetry    *telemetry
+
+	metadataTypes map[string]MetadataType
 }
 
 // NewClient creates a new Vimond REST API Client
@@ -51,6 +70,7 @@ func NewClient(options ...func(*Client)) *Client {
 		},
 		userAgent:    defaultUserAgent,
 		headerAccept: defaultHeaderAccept,
+		rateLimiter:  &rateLimiter{},
 	}
 
 	for _, f := range options {
@@ -92,30 +112,114 @@ func Credentials(apiKey, secret string) func(*Client) {
 }
 
 func (c *Client) get(ctx context.Context, path string, query url.Values) (*http.Response, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, path, query, nil, c.setAuthorizationHeader())
-	if err != nil {
-		return nil, err
+	return c.do(ctx, http.MethodGet, path, query, nil)
+}
+
+func (c *Client) post(ctx context.Context, path string, query url.Values, body io.Reader) (*http.Response, error) {
+	return c.do(ctx, http.MethodPost, path, query, body)
+}
+
+func (c *Client) put(ctx context.Context, path string, query url.Values, body io.Reader) (*http.Response, error) {
+	return c.do(ctx, http.MethodPut, path, query, body)
+}
+
+func (c *Client) delete(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	return c.do(ctx, http.MethodDelete, path, query, nil)
+}
+
+// do sends a signed request, waiting for the rate limiter and retrying it
+// according to the retry policy of the client. The request is rebuilt, and
+// thereby re-signed, on every attempt.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body io.Reader, options ...func(*http.Request)) (*http.Response, error) {
+	attempts := c.retryPolicy.attempts(method)
+
+	var payload []byte
+
+	if body != nil && attempts > 1 {
+		b, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+
+		payload = b
 	}
 
-	return c.httpClient.Do(req)
+	for attempt := 1; ; attempt++ {
+		if payload != nil {
+			body = bytes.NewReader(payload)
+		}
+
+		if err := c.rateLimiter.wait(ctx, path); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.attempt(ctx, attempt, method, path, query, body, options...)
+
+		throttled := err == nil && resp.StatusCode == http.StatusTooManyRequests
+
+		var wait time.Duration
+
+		if throttled {
+			// The retry waits exactly as long as the rate limiter holds back
+			// other requests, so that a 429 is not waited for twice.
+			wait = c.retryPolicy.throttleDelay(resp)
+
+			c.rateLimiter.throttle(path, wait)
+		}
+
+		if attempt >= attempts || !shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+
+		if !throttled {
+			wait = c.retryPolicy.backoff(attempt, resp)
+		}
+
+		if resp != nil {
+			io.CopyN(ioutil.Discard, resp.Body, 64)
+			resp.Body.Close()
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
 }
 
-func (c *Client) post(ctx context.Context, path string, query url.Values, body io.Reader) (*http.Response, error) {
-	req, err := c.newRequest(ctx, http.MethodPost, path, query, body, c.setAuthorizationHeader())
+// attempt builds, signs and sends a single attempt of a request, traced as a
+// child span of the API call in ctx if telemetry is enabled.
+func (c *Client) attempt(ctx context.Context, n int, method, path string, query url.Values, body io.Reader, options ...func(*http.Request)) (*http.Response, error) {
+	ctx, span := c.telemetry.startAttempt(ctx, method, n)
+
+	req, err := c.newRequest(ctx, method, path, query, body, append([]func(*http.Request){c.setAuthorizationHeader()}, options...)...)
 	if err != nil {
+		span.end(nil, err)
 		return nil, err
 	}
 
-	return c.httpClient.Do(req)
+	resp, err := c.send(req)
+
+	span.end(resp, err)
+
+	return resp, err
 }
 
-func (c *Client) put(ctx context.Context, path string, query url.Values, body io.Reader) (*http.Response, error) {
-	req, err := c.newRequest(ctx, http.MethodPut, path, query, body, c.setAuthorizationHeader())
-	if err != nil {
-		return nil, err
+// send sends the request through the middleware of the client, and then the
+// HTTP client, or the cassette if one is recording or replaying.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	next := c.httpClient.Do
+
+	if c.cassette != nil {
+		next = func(req *http.Request) (*http.Response, error) {
+			return c.cassette.roundTrip(c, req)
+		}
+	}
+
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
 	}
 
-	return c.httpClient.Do(req)
+	return next(req)
 }
 
 func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader, options ...func(*http.Request)) (*http.Request, error) {
@@ -142,12 +246,57 @@ func (c *Client) newRequest(ctx context.Context, method, path string, query url.
 	}
 
 	req.Header.Add("Accept", c.headerAccept)
-	req.Header.Add("Content-Type", "application/json; v=3; charset=utf-8")
 	req.Header.Add("User-Agent", c.userAgent)
 
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json; v=3; charset=utf-8")
+	}
+
 	return req, nil
 }
 
+// multipartBody encodes the given fields and file as multipart/form-data,
+// returning the body and its Content-Type. The file is the "file" part.
+func multipartBody(fields url.Values, filename, mediaType string, data []byte) (io.Reader, string, error) {
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		for _, v := range fields[name] {
+			if err := mw.WriteField(name, v); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="file"; filename=%q`, filename)},
+		"Content-Type":        {mediaType},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err := part.Write(data); err != nil {
+		return nil, "", err
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return &body, mw.FormDataContentType(), nil
+}
+
+// contentType is a request option that overrides the default JSON
+// Content-Type, such as for multipart uploads.
+func contentType(ct string) func(*http.Request) {
+	return func(req *http.Request) {
+		req.Header.Set("Content-Type", ct)
+	}
+}
+
 func (c *Client) setAuthorizationHeader() func(*http.Request) {
 	return func(req *http.Request) {
 		if c.apiKey != "" && c.secret != "" {
@@ -86,6 +86,20 @@ func TestNewClient(t *testing.T) {
 			t.Fatalf("c.apiKey = %q, want %q", got, want)
 		}
 	})
+
+	t.Run("Retry", func(t *testing.T) {
+		c := NewClient(Retry(RetryPolicy{MaxAttempts: 5}))
+
+		want := RetryPolicy{
+			MaxAttempts: 5,
+			MinBackoff:  DefaultRetryPolicy.MinBackoff,
+			MaxBackoff:  DefaultRetryPolicy.MaxBackoff,
+		}
+
+		if got := c.retryPolicy; got == nil || *got != want {
+			t.Fatalf("c.retryPolicy = %+v, want %+v", got, want)
+		}
+	})
 }
 
 func TestNewRequest(t *testing.T) {
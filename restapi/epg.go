@@ -0,0 +1,264 @@
+package restapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Channel is a live channel of a platform.
+type Channel struct {
+	ID          string
+	Title       string
+	Description string
+	Enabled     bool
+}
+
+// Program is an entry in the EPG of a channel.
+type Program struct {
+	ID          string
+	ChannelID   string
+	AssetID     string
+	Title       string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Live        bool
+}
+
+// Duration returns the scheduled duration of the program.
+func (p Program) Duration() time.Duration {
+	return p.End.Sub(p.Start)
+}
+
+// ScheduleGap is a gap between two consecutive programs, or an overlap if
+// Duration is negative.
+type ScheduleGap struct {
+	Before   Program
+	After    Program
+	Duration time.Duration
+}
+
+// ScheduleGaps returns the gaps and overlaps between consecutive programs,
+// ordered by start time.
+func ScheduleGaps(programs []Program) []ScheduleGap {
+	sorted := slices.SortedFunc(slices.Values(programs), func(a, b Program) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	var gaps []ScheduleGap
+
+	for i := 1; i < len(sorted); i++ {
+		if d := sorted[i].Start.Sub(sorted[i-1].End); d != 0 {
+			gaps = append(gaps, ScheduleGap{Before: sorted[i-1], After: sorted[i], Duration: d})
+		}
+	}
+
+	return gaps
+}
+
+// Channels returns the channels of a platform.
+func (c *Client) Channels(ctx context.Context, platform string) (_ []Channel, err error) {
+	ctx, op := c.startOperation(ctx, "Channels", platform)
+	defer op.end(&err)
+
+	resp, err := c.get(ctx, fmt.Sprintf("/api/%s/channel", platform), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseChannels(resp.Body)
+}
+
+// Programs returns the programs of a channel that overlap the time window
+// from, to, ordered by start time.
+func (c *Client) Programs(ctx context.Context, platform, channelID string, from, to time.Time) (_ []Program, err error) {
+	ctx, op := c.startOperation(ctx, "Programs", platform)
+	defer op.end(&err)
+
+	query := url.Values{
+		"from": {from.UTC().Format(time.RFC3339)},
+		"to":   {to.UTC().Format(time.RFC3339)},
+	}
+
+	resp, err := c.get(ctx, fmt.Sprintf("/api/%s/channel/%s/programs", platform, channelID), query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	programs, err := parsePrograms(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(programs, func(a, b Program) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	return programs, nil
+}
+
+// Program returns an EPG program.
+func (c *Client) Program(ctx context.Context, platform, programID string) (_ *Program, err error) {
+	ctx, op := c.startOperation(ctx, "Program", platform)
+	defer op.end(&err)
+
+	resp, err := c.get(ctx, c.programPath(platform, programID), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseProgram(resp.Body)
+}
+
+// UpdateProgram moves an EPG program to the given start and end time.
+func (c *Client) UpdateProgram(ctx context.Context, platform, programID string, start, end time.Time) (_ *Program, err error) {
+	ctx, op := c.startOperation(ctx, "UpdateProgram", platform)
+	defer op.end(&err)
+
+	if !end.After(start) {
+		return nil, fmt.Errorf("vimond/restapi: program end %s is not after start %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+
+	body, err := json.Marshal(struct {
+		StartTime time.Time `json:"startTime"`
+		EndTime   time.Time `json:"endTime"`
+	}{start, end})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.put(ctx, c.programPath(platform, programID), url.Values{}, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseProgram(resp.Body)
+}
+
+func (c *Client) programPath(platform, programID string) string {
+	return fmt.Sprintf("/api/%s/program/%s", platform, programID)
+}
+
+type vimondProgram struct {
+	ID          int       `json:"id"`
+	ChannelID   int       `json:"channelId"`
+	AssetID     int       `json:"assetId"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endTime"`
+	Live        bool      `json:"live"`
+}
+
+func (vp *vimondProgram) program() Program {
+	p := Program{
+		ID:          strconv.Itoa(vp.ID),
+		ChannelID:   strconv.Itoa(vp.ChannelID),
+		Title:       vp.Title,
+		Description: vp.Description,
+		Start:       vp.StartTime,
+		End:         vp.EndTime,
+		Live:        vp.Live,
+	}
+
+	if vp.AssetID != 0 {
+		p.AssetID = strconv.Itoa(vp.AssetID)
+	}
+
+	return p
+}
+
+func parseChannels(r io.Reader) ([]Channel, error) {
+	var resp []struct {
+		ID          int    `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Enabled     bool   `json:"enabled"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	channels := make([]Channel, 0, len(resp))
+
+	for n := range resp {
+		channels = append(channels, Channel{
+			ID:          strconv.Itoa(resp[n].ID),
+			Title:       resp[n].Title,
+			Description: resp[n].Description,
+			Enabled:     resp[n].Enabled,
+		})
+	}
+
+	return channels, nil
+}
+
+func parseProgram(r io.Reader) (*Program, error) {
+	var vp vimondProgram
+
+	if err := json.NewDecoder(r).Decode(&vp); err != nil {
+		return nil, err
+	}
+
+	p := vp.program()
+
+	return &p, nil
+}
+
+func parsePrograms(r io.Reader) ([]Program, error) {
+	var resp []vimondProgram
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	programs := make([]Program, 0, len(resp))
+
+	for n := range resp {
+		programs = append(programs, resp[n].program())
+	}
+
+	return programs, nil
+}
@@ -0,0 +1,113 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrograms(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/api/tv4/channel/5/programs"; got != want {
+			t.Errorf("r.URL.Path = %q, want %q", got, want)
+		}
+
+		if got, want := r.URL.Query().Get("from"), "2018-03-01T00:00:00Z"; got != want {
+			t.Errorf("from = %q, want %q", got, want)
+		}
+
+		if got, want := r.URL.Query().Get("to"), "2018-03-02T00:00:00Z"; got != want {
+			t.Errorf("to = %q, want %q", got, want)
+		}
+
+		w.Write([]byte(`[
+			{"id":2,"channelId":5,"title":"Sporten","startTime":"2018-03-01T19:00:00Z","endTime":"2018-03-01T21:00:00Z","live":true,"assetId":123},
+			{"id":1,"channelId":5,"title":"Nyheterna","startTime":"2018-03-01T18:00:00Z","endTime":"2018-03-01T18:45:00Z"}
+		]`))
+	})
+	defer ts.Close()
+
+	from := time.Date(2018, 3, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	programs, err := c.Programs(context.Background(), "tv4", "5", from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Program{
+		{
+			ID:        "1",
+			ChannelID: "5",
+			Title:     "Nyheterna",
+			Start:     time.Date(2018, 3, 1, 18, 0, 0, 0, time.UTC),
+			End:       time.Date(2018, 3, 1, 18, 45, 0, 0, time.UTC),
+		},
+		{
+			ID:        "2",
+			ChannelID: "5",
+			AssetID:   "123",
+			Title:     "Sporten",
+			Start:     time.Date(2018, 3, 1, 19, 0, 0, 0, time.UTC),
+			End:       time.Date(2018, 3, 1, 21, 0, 0, 0, time.UTC),
+			Live:      true,
+		},
+	}
+
+	if !cmp.Equal(programs, want) {
+		t.Errorf("programs mismatch (-got +want):\n%s", cmp.Diff(programs, want))
+	}
+
+	gaps := ScheduleGaps(programs)
+
+	if len(gaps) != 1 {
+		t.Fatalf("len(gaps) = %d, want 1", len(gaps))
+	}
+
+	if got, want := gaps[0].Duration, 15*time.Minute; got != want {
+		t.Errorf("gaps[0].Duration = %v, want %v", got, want)
+	}
+}
+
+func TestUpdateProgram(t *testing.T) {
+	start := time.Date(2018, 3, 1, 19, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Method+" "+r.URL.Path, "PUT /api/tv4/program/2"; got != want {
+			t.Errorf("request = %q, want %q", got, want)
+		}
+
+		var body struct {
+			StartTime time.Time `json:"startTime"`
+			EndTime   time.Time `json:"endTime"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if !body.StartTime.Equal(start) || !body.EndTime.Equal(end) {
+			t.Errorf("body = %v - %v, want %v - %v", body.StartTime, body.EndTime, start, end)
+		}
+
+		w.Write([]byte(`{"id":2,"channelId":5,"startTime":"2018-03-01T19:00:00Z","endTime":"2018-03-01T21:00:00Z"}`))
+	})
+	defer ts.Close()
+
+	p, err := c.UpdateProgram(context.Background(), "tv4", "2", start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := p.Duration(), 2*time.Hour; got != want {
+		t.Errorf("p.Duration() = %v, want %v", got, want)
+	}
+
+	if _, err := c.UpdateProgram(context.Background(), "tv4", "2", end, start); err == nil {
+		t.Errorf("expected error for end before start")
+	}
+}
@@ -0,0 +1,170 @@
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	headerRequestID   = "X-Request-Id"
+	maxErrorBodyBytes = 64 << 10
+)
+
+// errorCodes maps Vimond error codes to the errors they match.
+var errorCodes = map[string]error{
+	"VOUCHER_EXPIRED":      ErrVoucherExpired,
+	"VOUCHER_NOT_STARTED":  ErrVoucherInvalid,
+	"VOUCHER_INVALID":      ErrVoucherInvalid,
+	"VOUCHER_NOT_FOUND":    ErrVoucherInvalid,
+	"VOUCHER_USED":         ErrVoucherUsed,
+	"VOUCHER_ALREADY_USED": ErrVoucherUsed,
+}
+
+// APIError is returned when the Vimond REST API responds with an unexpected
+// status code. It matches ErrNotFound or ErrUnknown when used with errors.Is,
+// and errors such as ErrVoucherExpired depending on the Vimond error code.
+type APIError struct {
+	StatusCode  int
+	Method      string
+	Path        string
+	Code        string
+	Description string
+	RequestID   string
+
+	// unknown makes a 404 match ErrUnknown rather than ErrNotFound, for
+	// endpoints that have always returned ErrUnknown for any status.
+	unknown bool
+
+	// voucher makes a 404 match ErrVoucherInvalid as well, for voucher
+	// lookups where it means that the code is unknown.
+	voucher bool
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "vimond/restapi: %s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+
+	if e.Code != "" {
+		fmt.Fprintf(&b, ": %s", e.Code)
+	}
+
+	if e.Description != "" {
+		fmt.Fprintf(&b, ": %s", e.Description)
+	}
+
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request id %s)", e.RequestID)
+	}
+
+	return b.String()
+}
+
+// Is reports whether the error matches target. A 404 matches ErrNotFound,
+// any other status matches ErrUnknown, except for endpoints such as Platforms
+// where every status matches ErrUnknown. Known Vimond error codes match their
+// corresponding errors, and a 404 from a voucher lookup matches
+// ErrVoucherInvalid.
+func (e *APIError) Is(target error) bool {
+	if err, ok := errorCodes[e.Code]; ok && err == target {
+		return true
+	}
+
+	switch target {
+	case ErrVoucherInvalid:
+		return e.StatusCode == http.StatusNotFound && e.voucher
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound && !e.unknown
+	case ErrUnknown:
+		return e.StatusCode != http.StatusNotFound || e.unknown
+	}
+
+	return false
+}
+
+// newAPIError creates an *APIError from resp, reading the Vimond error body
+// if there is one.
+func newAPIError(resp *http.Response) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get(headerRequestID),
+	}
+
+	if req := resp.Request; req != nil {
+		e.Method = req.Method
+		e.Path = req.URL.Path
+	}
+
+	e.Code, e.Description = parseErrorBody(io.LimitReader(resp.Body, maxErrorBodyBytes))
+
+	return e
+}
+
+// newUnknownAPIError creates an *APIError from resp that matches ErrUnknown
+// for any status, including 404.
+func newUnknownAPIError(resp *http.Response) *APIError {
+	e := newAPIError(resp)
+	e.unknown = true
+
+	return e
+}
+
+// newVoucherAPIError creates an *APIError from the response to a voucher
+// lookup, where a 404 also matches ErrVoucherInvalid.
+func newVoucherAPIError(resp *http.Response) *APIError {
+	e := newAPIError(resp)
+	e.voucher = true
+
+	return e
+}
+
+// parseErrorBody extracts the error code and description from a Vimond
+// error body. Both the wrapped {"error": {...}} form and the flat form are
+// supported.
+func parseErrorBody(r io.Reader) (code, description string) {
+	type errorBody struct {
+		Code        json.RawMessage `json:"code"`
+		Description string          `json:"description"`
+		Message     string          `json:"message"`
+	}
+
+	var body struct {
+		Error json.RawMessage `json:"error"`
+
+		errorBody
+	}
+
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return "", ""
+	}
+
+	eb := body.errorBody
+
+	if len(body.Error) > 0 {
+		if err := json.Unmarshal(body.Error, &eb); err != nil {
+			eb.Description = rawString(body.Error)
+		}
+	}
+
+	description = eb.Description
+	if description == "" {
+		description = eb.Message
+	}
+
+	return rawString(eb.Code), description
+}
+
+// rawString returns the raw JSON value as a string, unquoting it if needed.
+func rawString(raw json.RawMessage) string {
+	var s string
+
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	return string(raw)
+}
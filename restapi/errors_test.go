@@ -0,0 +1,95 @@
+package restapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		status      int
+		body        string
+		code        string
+		description string
+		notFound    bool
+	}{
+		{"unauthorized", http.StatusUnauthorized, `{"error":{"code":"AUTHENTICATION_FAILED","description":"Invalid signature"}}`, "AUTHENTICATION_FAILED", "Invalid signature", false},
+		{"bad_request", http.StatusBadRequest, `{"code":1002,"description":"Invalid value"}`, "1002", "Invalid value", false},
+		{"not_found", http.StatusNotFound, `{"error":"Not found"}`, "", "Not found", true},
+		{"internal_server_error", http.StatusInternalServerError, "not-json", "", "", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "foo-request-id")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+			defer ts.Close()
+
+			_, err := c.Order(context.Background(), "foo-platform", "123")
+
+			var apiErr *APIError
+
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want *APIError", err)
+			}
+
+			if got, want := apiErr.StatusCode, tt.status; got != want {
+				t.Errorf("apiErr.StatusCode = %d, want %d", got, want)
+			}
+
+			if got, want := apiErr.Method, http.MethodGet; got != want {
+				t.Errorf("apiErr.Method = %q, want %q", got, want)
+			}
+
+			if got, want := apiErr.Path, "/api/foo-platform/order/123"; got != want {
+				t.Errorf("apiErr.Path = %q, want %q", got, want)
+			}
+
+			if got, want := apiErr.Code, tt.code; got != want {
+				t.Errorf("apiErr.Code = %q, want %q", got, want)
+			}
+
+			if got, want := apiErr.Description, tt.description; got != want {
+				t.Errorf("apiErr.Description = %q, want %q", got, want)
+			}
+
+			if got, want := apiErr.RequestID, "foo-request-id"; got != want {
+				t.Errorf("apiErr.RequestID = %q, want %q", got, want)
+			}
+
+			if got, want := errors.Is(err, ErrNotFound), tt.notFound; got != want {
+				t.Errorf("errors.Is(err, ErrNotFound) = %v, want %v", got, want)
+			}
+
+			if got, want := errors.Is(err, ErrUnknown), !tt.notFound; got != want {
+				t.Errorf("errors.Is(err, ErrUnknown) = %v, want %v", got, want)
+			}
+
+			if got, want := err.Error(), "foo-request-id"; !strings.Contains(got, want) {
+				t.Errorf("err.Error() = %q, want substring %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPlatformsErrorUnknown(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer ts.Close()
+
+	_, err := c.Platforms(context.Background())
+
+	if !errors.Is(err, ErrUnknown) {
+		t.Errorf("errors.Is(err, ErrUnknown) = false, want true")
+	}
+
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("errors.Is(err, ErrNotFound) = true, want false")
+	}
+}
@@ -0,0 +1,199 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AssetImage is a version of an image in the image pack of an asset.
+type AssetImage struct {
+	ID      string
+	Type    string
+	URL     string
+	Width   int
+	Height  int
+	Default bool
+	Created time.Time
+}
+
+// AssetImages returns the image pack of an asset.
+func (c *Client) AssetImages(ctx context.Context, platform, assetID string) (_ []AssetImage, err error) {
+	ctx, op := c.startOperation(ctx, "AssetImages", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	resp, err := c.get(ctx, c.assetPath(platform, assetID)+"/images", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseAssetImages(resp.Body)
+}
+
+// UploadAssetImage uploads an image to the image pack of an asset, with the
+// given image type, such as "poster" or "thumbnail". The image is read into
+// memory and must be a GIF, JPEG, PNG or WebP image.
+func (c *Client) UploadAssetImage(ctx context.Context, platform, assetID, imageType, filename string, r io.Reader) (_ *AssetImage, err error) {
+	ctx, op := c.startOperation(ctx, "UploadAssetImage", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	image, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType := http.DetectContentType(image)
+
+	if !strings.HasPrefix(mediaType, "image/") {
+		return nil, fmt.Errorf("vimond/restapi: %s is not an image (%s)", filename, mediaType)
+	}
+
+	body, ct, err := multipartBody(url.Values{"type": {imageType}}, filename, mediaType, image)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, c.assetPath(platform, assetID)+"/images", url.Values{}, body, contentType(ct))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, newAPIError(resp)
+	}
+
+	return parseAssetImage(resp.Body)
+}
+
+// SetDefaultAssetImage makes an image the default image of its type.
+func (c *Client) SetDefaultAssetImage(ctx context.Context, platform, assetID, imageID string) (err error) {
+	ctx, op := c.startOperation(ctx, "SetDefaultAssetImage", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return ErrInvalidAssetID
+	}
+
+	resp, err := c.put(ctx, c.assetImagePath(platform, assetID, imageID)+"/default", url.Values{}, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+// DeleteAssetImage deletes an image version from the image pack of an asset.
+func (c *Client) DeleteAssetImage(ctx context.Context, platform, assetID, imageID string) (err error) {
+	ctx, op := c.startOperation(ctx, "DeleteAssetImage", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return ErrInvalidAssetID
+	}
+
+	resp, err := c.delete(ctx, c.assetImagePath(platform, assetID, imageID), url.Values{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+func (c *Client) assetImagePath(platform, assetID, imageID string) string {
+	return c.assetPath(platform, assetID) + "/images/" + url.PathEscape(imageID)
+}
+
+type vimondAssetImage struct {
+	ID         int       `json:"id"`
+	Type       string    `json:"type"`
+	URL        string    `json:"url"`
+	Width      int       `json:"width"`
+	Height     int       `json:"height"`
+	Default    bool      `json:"default"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+func (vai *vimondAssetImage) assetImage() AssetImage {
+	return AssetImage{
+		ID:      strconv.Itoa(vai.ID),
+		Type:    vai.Type,
+		URL:     vai.URL,
+		Width:   vai.Width,
+		Height:  vai.Height,
+		Default: vai.Default,
+		Created: vai.CreateTime,
+	}
+}
+
+func parseAssetImage(r io.Reader) (*AssetImage, error) {
+	var vai vimondAssetImage
+
+	if err := json.NewDecoder(r).Decode(&vai); err != nil {
+		return nil, err
+	}
+
+	ai := vai.assetImage()
+
+	return &ai, nil
+}
+
+func parseAssetImages(r io.Reader) ([]AssetImage, error) {
+	var resp struct {
+		Images []vimondAssetImage `json:"images"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	images := make([]AssetImage, 0, len(resp.Images))
+
+	for n := range resp.Images {
+		images = append(images, resp.Images[n].assetImage())
+	}
+
+	return images, nil
+}
@@ -0,0 +1,122 @@
+package restapi
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// png is the signature of a PNG image, enough for content sniffing.
+var png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestAssetImages(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/api/tv4/asset/123/images"; got != want {
+			t.Errorf("r.URL.Path = %q, want %q", got, want)
+		}
+
+		w.Write([]byte(`{"images":[{"id":7,"type":"poster","url":"https://img/7.jpg","width":1280,"height":720,"default":true,"createTime":"2018-01-02T03:04:05Z"}]}`))
+	})
+	defer ts.Close()
+
+	images, err := c.AssetImages(context.Background(), "tv4", "123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []AssetImage{{
+		ID:      "7",
+		Type:    "poster",
+		URL:     "https://img/7.jpg",
+		Width:   1280,
+		Height:  720,
+		Default: true,
+		Created: time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}
+
+	if !cmp.Equal(images, want) {
+		t.Errorf("images mismatch (-got +want):\n%s", cmp.Diff(images, want))
+	}
+}
+
+func TestUploadAssetImage(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			if got, want := r.Method, http.MethodPost; got != want {
+				t.Errorf("r.Method = %q, want %q", got, want)
+			}
+
+			if got, want := r.Header.Get("Content-Type"), "multipart/form-data"; !strings.HasPrefix(got, want) {
+				t.Errorf("Content-Type = %q, want prefix %q", got, want)
+			}
+
+			if got, want := r.FormValue("type"), "thumbnail"; got != want {
+				t.Errorf(`r.FormValue("type") = %q, want %q`, got, want)
+			}
+
+			f, fh, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			defer f.Close()
+
+			if got, want := fh.Filename, "thumb.png"; got != want {
+				t.Errorf("fh.Filename = %q, want %q", got, want)
+			}
+
+			if got, want := fh.Header.Get("Content-Type"), "image/png"; got != want {
+				t.Errorf("part Content-Type = %q, want %q", got, want)
+			}
+
+			if b, _ := ioutil.ReadAll(f); !bytes.Equal(b, png) {
+				t.Errorf("unexpected file content %q", b)
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":8,"type":"thumbnail","url":"https://img/8.png"}`))
+		})
+		defer ts.Close()
+
+		image, err := c.UploadAssetImage(context.Background(), "tv4", "123", "thumbnail", "thumb.png", bytes.NewReader(png))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := image.ID, "8"; got != want {
+			t.Errorf("image.ID = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("NotAnImage", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request")
+		})
+		defer ts.Close()
+
+		if _, err := c.UploadAssetImage(context.Background(), "tv4", "123", "thumbnail", "thumb.png", strings.NewReader("foo")); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestDeleteAssetImage(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Method+" "+r.URL.Path, "DELETE /api/tv4/asset/123/images/7"; got != want {
+			t.Errorf("request = %q, want %q", got, want)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer ts.Close()
+
+	if err := c.DeleteAssetImage(context.Background(), "tv4", "123", "7"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
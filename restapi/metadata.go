@@ -0,0 +1,476 @@
+package restapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata keys used by TV4. Other platforms may use any other keys, which
+// are kept in AssetMetadata.All all the same.
+const (
+	MetadataAnnotags               = "annotags"
+	MetadataAssetLength            = "asset-length"
+	MetadataContentAPIID           = "content-api-id"
+	MetadataContentAPISeasonID     = "content-api-season-id"
+	MetadataContentAPISeriesID     = "content-api-series-id"
+	MetadataContentSource          = "content-source"
+	MetadataDescriptionShort       = "description-short"
+	MetadataEpisode                = "episode"
+	MetadataGenre                  = "genre"
+	MetadataGenreDescription       = "genre-description"
+	MetadataHideAds                = "hideAds"
+	MetadataImagePack              = "image-pack"
+	MetadataJuneMediaID            = "june-media-id"
+	MetadataJuneProgramID          = "june-program-id"
+	MetadataLouisePressTitle       = "louise-press-title"
+	MetadataLouiseProductKey       = "louise-product-key"
+	MetadataSeason                 = "season"
+	MetadataSeasonID               = "season-id"
+	MetadataSeasonSynopsis         = "season-synopsis"
+	MetadataSeriesDescriptionShort = "series-description-short"
+	MetadataSeriesID               = "series-id"
+	MetadataTitle                  = "title"
+	MetadataYouTubeTemplate        = "youtube-template"
+)
+
+// MetadataType is the type of the values of a metadata entry, which values
+// are validated against before they are written.
+type MetadataType int
+
+// Metadata types
+const (
+	MetadataString MetadataType = iota
+	MetadataInt
+	MetadataBool
+	MetadataTime
+	MetadataList
+)
+
+// String returns the name of the metadata type.
+func (mt MetadataType) String() string {
+	switch mt {
+	case MetadataString:
+		return "string"
+	case MetadataInt:
+		return "int"
+	case MetadataBool:
+		return "bool"
+	case MetadataTime:
+		return "time"
+	case MetadataList:
+		return "list"
+	}
+
+	return "MetadataType(" + strconv.Itoa(int(mt)) + ")"
+}
+
+// defaultMetadataTypes are the types of the known metadata keys, any other
+// keys are strings.
+var defaultMetadataTypes = map[string]MetadataType{
+	MetadataAnnotags:    MetadataList,
+	MetadataAssetLength: MetadataInt,
+	MetadataEpisode:     MetadataInt,
+	MetadataHideAds:     MetadataBool,
+	MetadataSeason:      MetadataInt,
+}
+
+// MetadataTypes adds or overrides the types of metadata keys used to validate
+// values written by SetAssetMetadata and UpdateAssetMetadata.
+func MetadataTypes(types map[string]MetadataType) func(*Client) {
+	return func(c *Client) {
+		if c.metadataTypes == nil {
+			c.metadataTypes = maps.Clone(defaultMetadataTypes)
+		}
+
+		maps.Copy(c.metadataTypes, types)
+	}
+}
+
+// InvalidMetadataError is returned when a metadata value does not match the
+// type of its key.
+type InvalidMetadataError struct {
+	Name  string
+	Lang  string
+	Value string
+	Type  MetadataType
+}
+
+// Error implements the error interface
+func (e *InvalidMetadataError) Error() string {
+	return fmt.Sprintf("vimond/restapi: invalid metadata %s (%s): %q is not a valid %s", e.Name, e.Lang, e.Value, e.Type)
+}
+
+// MetadataOp is the operation of a MetadataChange.
+type MetadataOp int
+
+// Metadata operations
+const (
+	// MetadataAdd sets the values of the given languages, keeping the values
+	// of other languages.
+	MetadataAdd MetadataOp = iota
+
+	// MetadataReplace replaces all values of the entry with the given values.
+	MetadataReplace
+
+	// MetadataRemove removes the values of the given languages, or the entire
+	// entry if no languages are given.
+	MetadataRemove
+)
+
+// MetadataChange is a change to a single metadata entry, see AddMetadata,
+// ReplaceMetadata and RemoveMetadata.
+type MetadataChange struct {
+	Op     MetadataOp
+	Name   string
+	Values LocalizedField
+}
+
+// AddMetadata returns a change that sets the given localized values of an
+// entry, keeping the values of other languages.
+func AddMetadata(name string, values ...LocalizedValue) MetadataChange {
+	return MetadataChange{Op: MetadataAdd, Name: name, Values: values}
+}
+
+// ReplaceMetadata returns a change that replaces all values of an entry.
+func ReplaceMetadata(name string, values ...LocalizedValue) MetadataChange {
+	return MetadataChange{Op: MetadataReplace, Name: name, Values: values}
+}
+
+// RemoveMetadata returns a change that removes the values of the given langs
+// from an entry, or the entire entry if no langs are given.
+func RemoveMetadata(name string, langs ...string) MetadataChange {
+	mc := MetadataChange{Op: MetadataRemove, Name: name}
+
+	for _, lang := range langs {
+		mc.Values = append(mc.Values, LocalizedValue{Lang: lang})
+	}
+
+	return mc
+}
+
+// SetAssetMetadata replaces all metadata entries of an asset with the given
+// entries, after validating their values. The resulting entries are returned.
+func (c *Client) SetAssetMetadata(ctx context.Context, platform, assetID string, entries MetadataMap) (_ MetadataMap, err error) {
+	ctx, op := c.startOperation(ctx, "SetAssetMetadata", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		if err := c.validateMetadata(name, entries[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.putMetadata(ctx, platform, assetID, entries)
+}
+
+// UpdateAssetMetadata applies the given changes to the metadata entries of an
+// asset, leaving other entries as they are. The metadata is fetched, changed
+// and written back, so concurrent updates of the same asset may overwrite each
+// other. Only the values of changed entries are validated. The resulting
+// entries are returned.
+func (c *Client) UpdateAssetMetadata(ctx context.Context, platform, assetID string, changes ...MetadataChange) (_ MetadataMap, err error) {
+	ctx, op := c.startOperation(ctx, "UpdateAssetMetadata", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	entries, err := c.metadata(ctx, platform, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		entries = MetadataMap{}
+	}
+
+	for _, mc := range changes {
+		if err := entries.apply(mc); err != nil {
+			return nil, err
+		}
+
+		if mc.Op != MetadataRemove {
+			if err := c.validateMetadata(mc.Name, mc.Values); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return c.putMetadata(ctx, platform, assetID, entries)
+}
+
+func (c *Client) metadata(ctx context.Context, platform, assetID string) (MetadataMap, error) {
+	resp, err := c.get(ctx, c.assetPath(platform, assetID)+"/metadata", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseMetadata(resp.Body)
+}
+
+func (c *Client) putMetadata(ctx context.Context, platform, assetID string, entries MetadataMap) (MetadataMap, error) {
+	body, err := json.Marshal(AssetMetadata{All: entries})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.put(ctx, c.assetPath(platform, assetID)+"/metadata", url.Values{}, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseMetadata(resp.Body)
+}
+
+func (c *Client) validateMetadata(name string, lf LocalizedField) error {
+	if name == "" {
+		return fmt.Errorf("vimond/restapi: empty metadata name")
+	}
+
+	types := c.metadataTypes
+	if types == nil {
+		types = defaultMetadataTypes
+	}
+
+	mt := types[name]
+
+	for _, lv := range lf {
+		if lv.Lang == "" {
+			return fmt.Errorf("vimond/restapi: metadata %s: empty lang", name)
+		}
+
+		if !mt.valid(lv.Value) {
+			return &InvalidMetadataError{Name: name, Lang: lv.Lang, Value: lv.Value, Type: mt}
+		}
+	}
+
+	return nil
+}
+
+func (mt MetadataType) valid(v string) bool {
+	v = strings.TrimSpace(v)
+
+	switch mt {
+	case MetadataInt:
+		_, err := strconv.Atoi(v)
+		return err == nil
+	case MetadataBool:
+		_, err := strconv.ParseBool(v)
+		return err == nil
+	case MetadataTime:
+		_, err := parseMetadataTime(v)
+		return err == nil
+	}
+
+	return true
+}
+
+func (me MetadataMap) apply(mc MetadataChange) error {
+	switch mc.Op {
+	case MetadataAdd:
+		lf := slices.Clone(me[mc.Name])
+
+		for _, lv := range mc.Values {
+			if i := slices.IndexFunc(lf, func(l LocalizedValue) bool { return l.Lang == lv.Lang }); i >= 0 {
+				lf[i] = lv
+			} else {
+				lf = append(lf, lv)
+			}
+		}
+
+		me[mc.Name] = lf
+	case MetadataReplace:
+		me[mc.Name] = slices.Clone(mc.Values)
+	case MetadataRemove:
+		if len(mc.Values) == 0 {
+			delete(me, mc.Name)
+			break
+		}
+
+		lf := slices.DeleteFunc(slices.Clone(me[mc.Name]), func(l LocalizedValue) bool {
+			return slices.ContainsFunc(mc.Values, func(lv LocalizedValue) bool { return lv.Lang == l.Lang })
+		})
+
+		if len(lf) == 0 {
+			delete(me, mc.Name)
+		} else {
+			me[mc.Name] = lf
+		}
+	default:
+		return fmt.Errorf("vimond/restapi: invalid metadata op %d", mc.Op)
+	}
+
+	return nil
+}
+
+func parseMetadata(r io.Reader) (MetadataMap, error) {
+	var am AssetMetadata
+
+	if err := json.NewDecoder(r).Decode(&am); err != nil {
+		return nil, err
+	}
+
+	return am.All, nil
+}
+
+// MetadataMap is metadata for an Asset in the Vimond Rest API, keyed by
+// metadata name.
+//
+// The typed getters look up the value of a key in the given languages, in
+// order of preference, falling back to the "*" language. They return
+// ErrMissingMetadata if the key has no such value.
+type MetadataMap map[string]LocalizedField
+
+// String returns the value of the named entry.
+func (me MetadataMap) String(name string, langs ...string) (string, error) {
+	v, ok := me[name].Lookup(langs...)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrMissingMetadata, name)
+	}
+
+	return v, nil
+}
+
+// Int returns the value of the named entry as an int.
+func (me MetadataMap) Int(name string, langs ...string) (int, error) {
+	v, err := me.String(name, langs...)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return 0, fmt.Errorf("vimond/restapi: metadata %s: invalid int %q", name, v)
+	}
+
+	return n, nil
+}
+
+// Bool returns the value of the named entry as a bool. Values are parsed by
+// strconv.ParseBool.
+func (me MetadataMap) Bool(name string, langs ...string) (bool, error) {
+	v, err := me.String(name, langs...)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		return false, fmt.Errorf("vimond/restapi: metadata %s: invalid bool %q", name, v)
+	}
+
+	return b, nil
+}
+
+// Time returns the value of the named entry as a time. Both RFC 3339
+// timestamps and plain dates (2006-01-02) are accepted.
+func (me MetadataMap) Time(name string, langs ...string) (time.Time, error) {
+	v, err := me.String(name, langs...)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := parseMetadataTime(strings.TrimSpace(v))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("vimond/restapi: metadata %s: invalid time %q", name, v)
+	}
+
+	return t, nil
+}
+
+// List returns the value of the named entry as a comma separated list.
+// Surrounding whitespace and empty items are dropped.
+func (me MetadataMap) List(name string, langs ...string) ([]string, error) {
+	v, err := me.String(name, langs...)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []string
+
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list, nil
+}
+
+// LocalizedField is field with localized values
+type LocalizedField []LocalizedValue
+
+// Value returns the value for the given lang, fallback to *
+func (lf LocalizedField) Value(lang string) string {
+	v, _ := lf.Lookup(lang)
+
+	return v
+}
+
+// Lookup returns the value for the first of the given langs that has one,
+// fallback to the last * value. The second return value is false if there is
+// no such value.
+func (lf LocalizedField) Lookup(langs ...string) (string, bool) {
+	for _, lang := range langs {
+		for _, l := range lf {
+			if l.Lang == lang {
+				return l.Value, true
+			}
+		}
+	}
+
+	for i := len(lf) - 1; i >= 0; i-- {
+		if lf[i].Lang == "*" {
+			return lf[i].Value, true
+		}
+	}
+
+	return "", false
+}
+
+// LocalizedValue is a representation of a multi-language value
+type LocalizedValue struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+func parseMetadataTime(v string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Parse("2006-01-02", v)
+	}
+
+	return t, err
+}
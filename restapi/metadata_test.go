@@ -0,0 +1,246 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLocalizedFieldLookup(t *testing.T) {
+	lf := LocalizedField{
+		{"*", "Foo"},
+		{"sv_SE", "Bar"},
+		{"nb_NO", "Baz"},
+	}
+
+	for _, tt := range []struct {
+		langs  []string
+		want   string
+		wantOK bool
+	}{
+		{nil, "Foo", true},
+		{[]string{"sv_SE"}, "Bar", true},
+		{[]string{"da_DK", "nb_NO", "sv_SE"}, "Baz", true},
+		{[]string{"da_DK", "fi_FI"}, "Foo", true},
+	} {
+		got, ok := lf.Lookup(tt.langs...)
+
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("lf.Lookup(%q) = %q, %v, want %q, %v", tt.langs, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if got, ok := (LocalizedField{{"sv_SE", "Bar"}}).Lookup("da_DK"); got != "" || ok {
+		t.Errorf(`Lookup("da_DK") = %q, %v, want "", false`, got, ok)
+	}
+
+	if got, want := (LocalizedField{{"*", "Foo"}, {"*", "Bar"}}).Value("da_DK"), "Bar"; got != want {
+		t.Errorf(`Value("da_DK") with two * values = %q, want the last one %q`, got, want)
+	}
+}
+
+func TestParseAssetUnknownMetadata(t *testing.T) {
+	asset, err := parseAsset(strings.NewReader(`{"id":1,"metadata":{"entries":{"title":[{"lang":"*","value":"Foo"}],"rating":[{"lang":"*","value":"15"}]}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := MetadataMap{
+		MetadataTitle: {{"*", "Foo"}},
+		"rating":      {{"*", "15"}},
+	}
+
+	if got := asset.Metadata.All; !cmp.Equal(got, want) {
+		t.Errorf("entries mismatch (-got +want):\n%s", cmp.Diff(got, want))
+	}
+
+	if got, want := asset.Metadata.Entries.Title.Value("*"), "Foo"; got != want {
+		t.Errorf("asset.Metadata.Entries.Title = %q, want %q", got, want)
+	}
+}
+
+func TestAssetMetadataMarshalJSON(t *testing.T) {
+	am := AssetMetadata{
+		Entries: MetadataEntries{
+			Title: LocalizedField{{"*", "Bar"}},
+		},
+		All: MetadataMap{
+			MetadataTitle: {{"*", "Foo"}},
+			"rating":      {{"*", "15"}},
+		},
+	}
+
+	b, err := json.Marshal(am)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := string(b), `{"entries":{"rating":[{"lang":"*","value":"15"}],"title":[{"lang":"*","value":"Bar"}]}}`; got != want {
+		t.Errorf("json = %s, want %s", got, want)
+	}
+}
+
+func TestMetadataMap(t *testing.T) {
+	me := MetadataMap{
+		MetadataEpisode: {{"*", " 12 "}},
+		MetadataHideAds: {{"*", "true"}},
+		MetadataGenre:   {{"*", "Drama, Crime,,"}, {"en_US", "Drama"}},
+		"air-date":      {{"*", "2018-03-01"}},
+		"updated":       {{"sv_SE", "2018-03-01T12:00:00Z"}},
+		"bad-number":    {{"*", "twelve"}},
+	}
+
+	t.Run("Int", func(t *testing.T) {
+		n, err := me.Int(MetadataEpisode)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := n, 12; got != want {
+			t.Errorf("n = %d, want %d", got, want)
+		}
+
+		if _, err := me.Int("bad-number"); err == nil {
+			t.Errorf("expected error for invalid int")
+		}
+	})
+
+	t.Run("Bool", func(t *testing.T) {
+		b, err := me.Bool(MetadataHideAds)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !b {
+			t.Errorf("b = false, want true")
+		}
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		for name, want := range map[string]time.Time{
+			"air-date": time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+			"updated":  time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC),
+		} {
+			got, err := me.Time(name, "sv_SE")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !got.Equal(want) {
+				t.Errorf("me.Time(%q) = %v, want %v", name, got, want)
+			}
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		list, err := me.List(MetadataGenre)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := list, []string{"Drama", "Crime"}; !cmp.Equal(got, want) {
+			t.Errorf("list = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		if _, err := me.String("updated"); !errors.Is(err, ErrMissingMetadata) {
+			t.Errorf("err = %v, want ErrMissingMetadata", err)
+		}
+
+		if _, err := me.Int(MetadataSeason); !errors.Is(err, ErrMissingMetadata) {
+			t.Errorf("err = %v, want ErrMissingMetadata", err)
+		}
+	})
+}
+
+func TestUpdateAssetMetadata(t *testing.T) {
+	var put AssetMetadata
+
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/api/tv4/asset/123/metadata"; got != want {
+			t.Errorf("r.URL.Path = %q, want %q", got, want)
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"entries":{
+				"title":[{"lang":"*","value":"Foo"},{"lang":"sv_SE","value":"Föö"}],
+				"genre":[{"lang":"*","value":"Drama"}],
+				"rating":[{"lang":"*","value":"15"}]
+			}}`))
+		case http.MethodPut:
+			if err := json.NewDecoder(r.Body).Decode(&put); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			json.NewEncoder(w).Encode(put)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+	defer ts.Close()
+
+	entries, err := c.UpdateAssetMetadata(context.Background(), "tv4", "123",
+		AddMetadata(MetadataTitle, LocalizedValue{"sv_SE", "Bar"}, LocalizedValue{"nb_NO", "Baz"}),
+		ReplaceMetadata(MetadataEpisode, LocalizedValue{"*", "3"}),
+		RemoveMetadata(MetadataGenre),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := MetadataMap{
+		MetadataTitle:   {{"*", "Foo"}, {"sv_SE", "Bar"}, {"nb_NO", "Baz"}},
+		MetadataEpisode: {{"*", "3"}},
+		"rating":        {{"*", "15"}},
+	}
+
+	if !cmp.Equal(put.All, want) {
+		t.Errorf("put.All = %v, want %v", put.All, want)
+	}
+
+	if !cmp.Equal(entries, want) {
+		t.Errorf("entries = %v, want %v", entries, want)
+	}
+}
+
+func TestSetAssetMetadataValidation(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	defer ts.Close()
+
+	_, err := c.SetAssetMetadata(context.Background(), "tv4", "123", MetadataMap{
+		MetadataTitle:   {{"*", "Foo"}},
+		MetadataEpisode: {{"*", "three"}},
+	})
+
+	var ime *InvalidMetadataError
+
+	if !errors.As(err, &ime) {
+		t.Fatalf("err = %v, want *InvalidMetadataError", err)
+	}
+
+	if got, want := ime.Name, MetadataEpisode; got != want {
+		t.Errorf("ime.Name = %q, want %q", got, want)
+	}
+
+	if got, want := ime.Type, MetadataInt; got != want {
+		t.Errorf("ime.Type = %v, want %v", got, want)
+	}
+
+	c = NewClient(BaseURL(ts.URL), MetadataTypes(map[string]MetadataType{"air-date": MetadataTime}))
+
+	if _, err := c.SetAssetMetadata(context.Background(), "tv4", "123", MetadataMap{
+		"air-date": {{"*", "yesterday"}},
+	}); !errors.As(err, &ime) {
+		t.Errorf("err = %v, want *InvalidMetadataError", err)
+	}
+}
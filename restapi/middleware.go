@@ -0,0 +1,51 @@
+package restapi
+
+import "net/http"
+
+// RoundTripFunc sends a request and returns its response, like the RoundTrip
+// method of an http.RoundTripper.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of requests by the *client, calling next to
+// send the request on.
+//
+// Middleware is called once per attempt, after the client has waited for the
+// rate limiter and signed the request, and sees the response before it is
+// checked for errors and retries. Headers can be added freely, but changing
+// the method, path or Date header invalidates the signature.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use adds middleware to the *client. The first middleware added is the
+// outermost, seeing the request first and the response last.
+func Use(middleware ...Middleware) func(*Client) {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// BeforeRequest makes the *client call f with every signed request before it
+// is sent. It is added as middleware, ordered like middleware added by Use.
+func BeforeRequest(f func(*http.Request)) func(*Client) {
+	return Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			f(req)
+
+			return next(req)
+		}
+	})
+}
+
+// AfterResponse makes the *client call f with the request and the response or
+// error of every attempt. It is added as middleware, ordered like middleware
+// added by Use.
+func AfterResponse(f func(*http.Request, *http.Response, error)) func(*Client) {
+	return Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+
+			f(req, resp, err)
+
+			return resp, err
+		}
+	})
+}
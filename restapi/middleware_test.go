@@ -0,0 +1,73 @@
+package restapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMiddleware(t *testing.T) {
+	var calls []string
+
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				if !strings.HasPrefix(req.Header.Get("Authorization"), "SUMO key:") {
+					t.Errorf("%s: request not signed", name)
+				}
+
+				calls = append(calls, name+" before")
+
+				resp, err := next(req)
+
+				calls = append(calls, name+" after")
+
+				return resp, err
+			}
+		}
+	}
+
+	attempt := 0
+
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("X-Request-Id"), "abc"; got != want {
+			t.Errorf("X-Request-Id = %q, want %q", got, want)
+		}
+
+		if attempt++; attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(`[]`))
+	},
+		Credentials("key", "secret"),
+		Retry(RetryPolicy{MaxAttempts: 2, MaxBackoff: time.Millisecond}),
+		Use(trace("outer")),
+		BeforeRequest(func(req *http.Request) {
+			req.Header.Set("X-Request-Id", "abc")
+		}),
+		AfterResponse(func(req *http.Request, resp *http.Response, err error) {
+			calls = append(calls, resp.Status)
+		}),
+		Use(trace("inner")),
+	)
+	defer ts.Close()
+
+	if _, err := c.Platforms(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"outer before", "inner before", "inner after", "503 Service Unavailable", "outer after",
+		"outer before", "inner before", "inner after", "200 OK", "outer after",
+	}
+
+	if !cmp.Equal(calls, want) {
+		t.Errorf("calls mismatch (-got +want):\n%s", cmp.Diff(calls, want))
+	}
+}
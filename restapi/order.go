@@ -9,23 +9,40 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Order holds a subset of the fields of an order.
 type Order struct {
-	AccessEndDate    time.Time
-	EndDate          time.Time
-	ID               string
-	ProductName      string
-	ProductPaymentID string
-	StartDate        time.Time
-	UserID           string
+	AccessEndDate     time.Time
+	AutoRenew         bool
+	Currency          string
+	EarlyEndDate      time.Time
+	EndDate           time.Time
+	ID                string
+	PaymentProviderID string
+	Period            string
+	PlatformID        string
+	Price             float64
+	ProductName       string
+	ProductPaymentID  string
+	Referrer          string
+	StartDate         time.Time
+	Status            string
+	TerminationReason string
+	UserID            string
+	VoucherCode       string
 }
 
 // Order returns information about an order.
-func (c *Client) Order(ctx context.Context, platform, orderID string) (*Order, error) {
+func (c *Client) Order(ctx context.Context, platform, orderID string) (_ *Order, err error) {
+	ctx, op := c.startOperation(ctx, "Order", platform, AttributeOrderID.String(orderID))
+	defer op.end(&err)
+
 	path := fmt.Sprintf("/api/%s/order/%s", platform, orderID)
 
 	resp, err := c.get(ctx, path, url.Values{})
@@ -37,20 +54,18 @@ func (c *Client) Order(ctx context.Context, platform, orderID string) (*Order, e
 		resp.Body.Close()
 	}()
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		break
-	case http.StatusNotFound:
-		return nil, ErrNotFound
-	default:
-		return nil, ErrUnknown
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
 	}
 
 	return parseOrder(resp.Body)
 }
 
 // CurrentOrders returns information about a user's currently active orders.
-func (c *Client) CurrentOrders(ctx context.Context, platform, userID string) ([]*Order, error) {
+func (c *Client) CurrentOrders(ctx context.Context, platform, userID string) (_ []*Order, err error) {
+	ctx, op := c.startOperation(ctx, "CurrentOrders", platform)
+	defer op.end(&err)
+
 	path := fmt.Sprintf("/api/%s/user/%s/orders/current", platform, userID)
 
 	resp, err := c.get(ctx, path, url.Values{})
@@ -62,26 +77,54 @@ func (c *Client) CurrentOrders(ctx context.Context, platform, userID string) ([]
 		resp.Body.Close()
 	}()
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		break
-	case http.StatusNotFound:
-		return nil, ErrNotFound
-	default:
-		return nil, ErrUnknown
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseOrders(resp.Body)
+}
+
+// OrderHistory returns all orders of a user, including expired and terminated
+// orders.
+func (c *Client) OrderHistory(ctx context.Context, platform, userID string) (_ []*Order, err error) {
+	ctx, op := c.startOperation(ctx, "OrderHistory", platform)
+	defer op.end(&err)
+
+	path := fmt.Sprintf("/api/%s/user/%s/orders/history", platform, userID)
+
+	resp, err := c.get(ctx, path, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
 	}
 
 	return parseOrders(resp.Body)
 }
 
 // CreateOrder creates an order.
-func (c *Client) CreateOrder(ctx context.Context, platform, userID, productPaymentID string) (*Order, error) {
+func (c *Client) CreateOrder(ctx context.Context, platform, userID, productPaymentID string) (_ *Order, err error) {
+	ctx, op := c.startOperation(ctx, "CreateOrder", platform)
+	defer op.end(&err)
+
+	return c.createOrder(ctx, platform, userID, productPaymentID, "")
+}
+
+func (c *Client) createOrder(ctx context.Context, platform, userID, productPaymentID, voucherCode string) (*Order, error) {
 	path := fmt.Sprintf("/api/%s/order/%s/create", platform, userID)
 
 	body, err := json.Marshal(struct {
 		ProductPaymentID string `json:"productPaymentId"`
+		VoucherCode      string `json:"voucherCode,omitempty"`
 	}{
 		ProductPaymentID: productPaymentID,
+		VoucherCode:      voucherCode,
 	})
 	if err != nil {
 		return nil, err
@@ -96,89 +139,201 @@ func (c *Client) CreateOrder(ctx context.Context, platform, userID, productPayme
 		resp.Body.Close()
 	}()
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		break
-	case http.StatusNotFound:
-		return nil, ErrNotFound
-	default:
-		return nil, ErrUnknown
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseOrder(resp.Body)
+}
+
+// TerminateOrder terminates an order, either immediately or at the end of the
+// current period.
+func (c *Client) TerminateOrder(ctx context.Context, platform, orderID string, immediately bool) (_ *Order, err error) {
+	ctx, op := c.startOperation(ctx, "TerminateOrder", platform, AttributeOrderID.String(orderID))
+	defer op.end(&err)
+
+	return c.orderAction(ctx, platform, orderID, "terminate", url.Values{"immediate": {strconv.FormatBool(immediately)}})
+}
+
+// CancelAutoRenew stops an order from being renewed at the end of the
+// current period.
+func (c *Client) CancelAutoRenew(ctx context.Context, platform, orderID string) (_ *Order, err error) {
+	ctx, op := c.startOperation(ctx, "CancelAutoRenew", platform, AttributeOrderID.String(orderID))
+	defer op.end(&err)
+
+	return c.orderAction(ctx, platform, orderID, "autorenew/cancel", url.Values{})
+}
+
+// ReactivateOrder reactivates a terminated order, or resumes the renewal of
+// an order with cancelled auto renewal.
+func (c *Client) ReactivateOrder(ctx context.Context, platform, orderID string) (_ *Order, err error) {
+	ctx, op := c.startOperation(ctx, "ReactivateOrder", platform, AttributeOrderID.String(orderID))
+	defer op.end(&err)
+
+	return c.orderAction(ctx, platform, orderID, "reactivate", url.Values{})
+}
+
+func (c *Client) orderAction(ctx context.Context, platform, orderID, action string, query url.Values) (*Order, error) {
+	path := fmt.Sprintf("/api/%s/order/%s/%s", platform, orderID, action)
+
+	resp, err := c.post(ctx, path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
 	}
 
 	return parseOrder(resp.Body)
 }
 
 // SetOrderEndDates updates the endData and accessEndDate fields of an order to
-// the given end date. This method fetches the given order, strips null values
-// and nested objects (the Vimond API explodes on them), sets the dates, and
-// PUTs the resulting object back. This may result in data loss. Use with
-// caution.
-func (c *Client) SetOrderEndDates(ctx context.Context, platform, orderID string, endDate time.Time) (*Order, error) {
-	return c.updateOrder(ctx, platform, orderID, map[string]interface{}{
+// the given end date. The order is updated using PatchOrder. Since the Vimond
+// API explodes on nested objects, writing the order back may result in data
+// loss, so a *FieldLossError is returned instead of updating orders with
+// nested objects, and if fields are missing after the update. Use PatchOrder
+// with AllowFieldLoss to update such orders anyway.
+func (c *Client) SetOrderEndDates(ctx context.Context, platform, orderID string, endDate time.Time) (_ *Order, err error) {
+	ctx, op := c.startOperation(ctx, "SetOrderEndDates", platform, AttributeOrderID.String(orderID))
+	defer op.end(&err)
+
+	res, err := c.PatchOrder(ctx, platform, orderID, map[string]interface{}{
 		"accessEndDate": endDate.Unix() * 1000,
 		"endDate":       endDate.Unix() * 1000,
-	})
+	}, PatchOrderOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Order, nil
 }
 
-// updateOrder updates an order by overwriting the given field values. This
-// method skips nested objects.
-func (c *Client) updateOrder(ctx context.Context, platform, orderID string, values map[string]interface{}) (*Order, error) {
-	getRawOrder := func(ctx context.Context, platform, orderID string) (map[string]interface{}, error) {
-		path := fmt.Sprintf("/api/%s/order/%s", platform, orderID)
+// PatchOrderOptions controls the behaviour of PatchOrder.
+type PatchOrderOptions struct {
+	// AllowFieldLoss makes PatchOrder write the order even though fields
+	// have to be left out of the update, and not fail if fields are missing
+	// after the update.
+	AllowFieldLoss bool
+}
 
-		resp, err := c.get(ctx, path, url.Values{})
-		if err != nil {
-			return nil, err
-		}
-		defer func() {
-			io.CopyN(ioutil.Discard, resp.Body, 64)
-			resp.Body.Close()
-		}()
-
-		switch resp.StatusCode {
-		case http.StatusOK:
-			break
-		case http.StatusNotFound:
-			return nil, ErrNotFound
-		default:
-			return nil, ErrUnknown
-		}
+// PatchOrderResult is the result of PatchOrder.
+type PatchOrderResult struct {
+	// Order is the updated order.
+	Order *Order
 
-		var m map[string]interface{}
+	// Changes are the fields that differ between the order before and
+	// after the update, sorted by field name.
+	Changes []FieldChange
 
-		if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
-			return nil, err
-		}
+	// Lost are the fields that were set before the update but are missing
+	// after it, sorted by field name.
+	Lost []string
+}
+
+// FieldChange is a changed field of a raw Vimond object.
+type FieldChange struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
 
-		return m, nil
+// FieldLossError is returned when an update would lose, or has lost, fields.
+type FieldLossError struct {
+	Fields []string
+}
+
+// Error implements the error interface
+func (e *FieldLossError) Error() string {
+	return "vimond/restapi: fields would be lost: " + strings.Join(e.Fields, ", ")
+}
+
+// PatchOrder updates an order by overwriting the given field values, keyed by
+// their Vimond JSON names. The raw order is fetched and written back with the
+// given values, keeping numbers as is. The Vimond API explodes on null values
+// and nested objects, so these are left out unless given in values.
+//
+// Leaving out nested objects may lose data, so PatchOrder refuses to write
+// the order if it has any, unless AllowFieldLoss is set. After the update,
+// the order is fetched again to verify that no fields were lost and to
+// report the changes. If fields were lost despite this, the result is
+// returned together with a *FieldLossError, unless AllowFieldLoss is set.
+func (c *Client) PatchOrder(ctx context.Context, platform, orderID string, values map[string]interface{}, opts PatchOrderOptions) (_ *PatchOrderResult, err error) {
+	ctx, op := c.startOperation(ctx, "PatchOrder", platform, AttributeOrderID.String(orderID))
+	defer op.end(&err)
+
+	before, err := c.rawOrder(ctx, platform, orderID)
+	if err != nil {
+		return nil, err
 	}
 
-	rawOrder, err := getRawOrder(ctx, platform, orderID)
+	rawOrder, omitted := orderUpdate(before, values)
+
+	if len(omitted) > 0 && !opts.AllowFieldLoss {
+		return nil, &FieldLossError{Fields: omitted}
+	}
+
+	body, err := json.Marshal(&rawOrder)
 	if err != nil {
 		return nil, err
 	}
 
-	// The Vimond API seems to explode on null values and nested objects, so
-	// remove these.
-	for k, v := range rawOrder {
-		if v == nil {
-			delete(rawOrder, k)
-			continue
-		}
-		if _, ok := v.(map[string]interface{}); ok {
-			delete(rawOrder, k)
-		}
+	order, err := c.putOrder(ctx, platform, orderID, body)
+	if err != nil {
+		return nil, err
 	}
 
-	for k, v := range values {
-		rawOrder[k] = v
+	after, err := c.rawOrder(ctx, platform, orderID)
+	if err != nil {
+		return nil, err
 	}
 
-	body, err := json.Marshal(&rawOrder)
+	res := &PatchOrderResult{
+		Order:   order,
+		Changes: diffFields(before, after),
+		Lost:    lostFields(before, after, values),
+	}
+
+	if len(res.Lost) > 0 && !opts.AllowFieldLoss {
+		return res, &FieldLossError{Fields: res.Lost}
+	}
+
+	return res, nil
+}
+
+func (c *Client) rawOrder(ctx context.Context, platform, orderID string) (map[string]interface{}, error) {
+	path := fmt.Sprintf("/api/%s/order/%s", platform, orderID)
+
+	resp, err := c.get(ctx, path, url.Values{})
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var m map[string]interface{}
 
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (c *Client) putOrder(ctx context.Context, platform, orderID string, body []byte) (*Order, error) {
 	path := fmt.Sprintf("/api/%s/order/%s", platform, orderID)
 
 	resp, err := c.put(ctx, path, url.Values{}, bytes.NewReader(body))
@@ -190,54 +345,146 @@ func (c *Client) updateOrder(ctx context.Context, platform, orderID string, valu
 		resp.Body.Close()
 	}()
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		break
-	case http.StatusNotFound:
-		return nil, ErrNotFound
-	default:
-		return nil, ErrUnknown
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
 	}
 
 	return parseOrder(resp.Body)
 }
 
-func parseOrder(r io.Reader) (*Order, error) {
-	var o struct {
-		AccessEndDate    time.Time `json:"accessEndDate"`
-		EndDate          time.Time `json:"endDate"`
-		ID               int       `json:"id"`
-		ProductName      string    `json:"productName"`
-		ProductPaymentID int       `json:"productPaymentID"`
-		StartDate        time.Time `json:"startDate"`
-		UserID           int       `json:"userId"`
+// orderUpdate returns the raw order to PUT, which is rawOrder without null
+// values and nested objects, overwritten with values. The names of the left
+// out nested objects are returned sorted.
+func orderUpdate(rawOrder, values map[string]interface{}) (map[string]interface{}, []string) {
+	update := make(map[string]interface{}, len(rawOrder)+len(values))
+
+	var omitted []string
+
+	for k, v := range rawOrder {
+		if v == nil {
+			continue
+		}
+
+		if _, ok := v.(map[string]interface{}); ok {
+			if _, ok := values[k]; !ok {
+				omitted = append(omitted, k)
+			}
+			continue
+		}
+
+		update[k] = v
 	}
 
+	for k, v := range values {
+		update[k] = v
+	}
+
+	sort.Strings(omitted)
+
+	return update, omitted
+}
+
+// diffFields returns the fields that differ between before and after,
+// sorted by field name.
+func diffFields(before, after map[string]interface{}) []FieldChange {
+	var changes []FieldChange
+
+	for k, v := range before {
+		if _, ok := after[k]; !ok && v != nil {
+			changes = append(changes, FieldChange{Field: k, Old: v})
+		}
+	}
+
+	for k, v := range after {
+		if old := before[k]; !reflect.DeepEqual(old, v) {
+			changes = append(changes, FieldChange{Field: k, Old: old, New: v})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes
+}
+
+// lostFields returns the fields that were set in before but are not set in
+// after, and not among the updated values, sorted by field name.
+func lostFields(before, after, values map[string]interface{}) []string {
+	var lost []string
+
+	for k, v := range before {
+		if _, ok := values[k]; ok || v == nil {
+			continue
+		}
+
+		if after[k] == nil {
+			lost = append(lost, k)
+		}
+	}
+
+	sort.Strings(lost)
+
+	return lost
+}
+
+// vimondOrder is the Vimond representation of an order, with numeric IDs.
+type vimondOrder struct {
+	AccessEndDate     time.Time `json:"accessEndDate"`
+	AutoRenew         bool      `json:"autoRenew"`
+	Currency          string    `json:"currency"`
+	EarlyEndDate      time.Time `json:"earlyEndDate"`
+	EndDate           time.Time `json:"endDate"`
+	ID                int       `json:"id"`
+	PaymentProviderID int       `json:"paymentProviderId"`
+	Period            string    `json:"period"`
+	PlatformID        int       `json:"platformId"`
+	Price             float64   `json:"price"`
+	ProductName       string    `json:"productName"`
+	ProductPaymentID  int       `json:"productPaymentID"`
+	Referrer          string    `json:"referrer"`
+	StartDate         time.Time `json:"startDate"`
+	Status            string    `json:"status"`
+	TerminationReason string    `json:"terminationReason"`
+	UserID            int       `json:"userId"`
+	VoucherCode       string    `json:"voucherCode"`
+}
+
+func (o *vimondOrder) order() *Order {
+	return &Order{
+		AccessEndDate:     o.AccessEndDate,
+		AutoRenew:         o.AutoRenew,
+		Currency:          o.Currency,
+		EarlyEndDate:      o.EarlyEndDate,
+		EndDate:           o.EndDate,
+		ID:                strconv.Itoa(o.ID),
+		PaymentProviderID: strconv.Itoa(o.PaymentProviderID),
+		Period:            o.Period,
+		PlatformID:        strconv.Itoa(o.PlatformID),
+		Price:             o.Price,
+		ProductName:       o.ProductName,
+		ProductPaymentID:  strconv.Itoa(o.ProductPaymentID),
+		Referrer:          o.Referrer,
+		StartDate:         o.StartDate,
+		Status:            o.Status,
+		TerminationReason: o.TerminationReason,
+		UserID:            strconv.Itoa(o.UserID),
+		VoucherCode:       o.VoucherCode,
+	}
+}
+
+func parseOrder(r io.Reader) (*Order, error) {
+	var o vimondOrder
+
 	if err := json.NewDecoder(r).Decode(&o); err != nil {
 		return nil, err
 	}
 
-	return &Order{
-		AccessEndDate:    o.AccessEndDate,
-		EndDate:          o.EndDate,
-		ID:               strconv.Itoa(o.ID),
-		ProductName:      o.ProductName,
-		ProductPaymentID: strconv.Itoa(o.ProductPaymentID),
-		StartDate:        o.StartDate,
-		UserID:           strconv.Itoa(o.UserID),
-	}, nil
+	return o.order(), nil
 }
 
 func parseOrders(r io.Reader) ([]*Order, error) {
-	var resp []struct {
-		AccessEndDate    time.Time `json:"accessEndDate"`
-		EndDate          time.Time `json:"endDate"`
-		ID               int       `json:"id"`
-		ProductName      string    `json:"productName"`
-		ProductPaymentID int       `json:"productPaymentID"`
-		StartDate        time.Time `json:"startDate"`
-		UserID           int       `json:"userId"`
-	}
+	var resp []vimondOrder
 
 	if err := json.NewDecoder(r).Decode(&resp); err != nil {
 		return nil, err
@@ -246,15 +493,7 @@ func parseOrders(r io.Reader) ([]*Order, error) {
 	orders := make([]*Order, 0, len(resp))
 
 	for n := range resp {
-		orders = append(orders, &Order{
-			AccessEndDate:    resp[n].AccessEndDate,
-			EndDate:          resp[n].EndDate,
-			ID:               strconv.Itoa(resp[n].ID),
-			ProductName:      resp[n].ProductName,
-			ProductPaymentID: strconv.Itoa(resp[n].ProductPaymentID),
-			StartDate:        resp[n].StartDate,
-			UserID:           strconv.Itoa(resp[n].UserID),
-		})
+		orders = append(orders, resp[n].order())
 	}
 
 	return orders, nil
@@ -1,9 +1,18 @@
 package restapi
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestParseOrder(t *testing.T) {
@@ -168,3 +177,322 @@ func TestParseOrders(t *testing.T) {
 		}
 	})
 }
+
+func TestParseOrderDetails(t *testing.T) {
+	const orderJSON = `
+	{
+		"autoRenew":         true,
+		"currency":          "SEK",
+		"earlyEndDate":      "2000-01-03T00:00:00Z",
+		"id":                123456,
+		"paymentProviderId": 12,
+		"period":            "P1M",
+		"platformId":        3,
+		"price":             99.5,
+		"referrer":          "foo-referrer",
+		"status":            "TERMINATED",
+		"terminationReason": "foo-reason",
+		"voucherCode":       "FOO-CODE"
+	}
+	`
+
+	order, err := parseOrder(strings.NewReader(orderJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := order.AutoRenew, true; got != want {
+		t.Errorf("order.AutoRenew = %v, want %v", got, want)
+	}
+
+	if got, want := order.Currency, "SEK"; got != want {
+		t.Errorf("order.Currency = %q, want %q", got, want)
+	}
+
+	if got, want := order.EarlyEndDate, time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("order.EarlyEndDate = %s, want %s", got.Format(time.RFC3339Nano), want.Format(time.RFC3339Nano))
+	}
+
+	if got, want := order.PaymentProviderID, "12"; got != want {
+		t.Errorf("order.PaymentProviderID = %q, want %q", got, want)
+	}
+
+	if got, want := order.Period, "P1M"; got != want {
+		t.Errorf("order.Period = %q, want %q", got, want)
+	}
+
+	if got, want := order.PlatformID, "3"; got != want {
+		t.Errorf("order.PlatformID = %q, want %q", got, want)
+	}
+
+	if got, want := order.Price, 99.5; got != want {
+		t.Errorf("order.Price = %v, want %v", got, want)
+	}
+
+	if got, want := order.Referrer, "foo-referrer"; got != want {
+		t.Errorf("order.Referrer = %q, want %q", got, want)
+	}
+
+	if got, want := order.Status, "TERMINATED"; got != want {
+		t.Errorf("order.Status = %q, want %q", got, want)
+	}
+
+	if got, want := order.TerminationReason, "foo-reason"; got != want {
+		t.Errorf("order.TerminationReason = %q, want %q", got, want)
+	}
+
+	if got, want := order.VoucherCode, "FOO-CODE"; got != want {
+		t.Errorf("order.VoucherCode = %q, want %q", got, want)
+	}
+}
+
+func TestOrderHistory(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/api/tv4/user/345678/orders/history"; got != want {
+			t.Errorf("r.URL.Path = %q, want %q", got, want)
+		}
+
+		w.Write([]byte(`[{"id":1,"status":"ACTIVE"},{"id":2,"status":"EXPIRED"}]`))
+	})
+	defer ts.Close()
+
+	orders, err := c.OrderHistory(context.Background(), "tv4", "345678")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := len(orders), 2; got != want {
+		t.Fatalf("got %d orders, want %d", got, want)
+	}
+
+	if got, want := orders[1].Status, "EXPIRED"; got != want {
+		t.Errorf("orders[1].Status = %q, want %q", got, want)
+	}
+}
+
+func TestOrderActions(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		do    func(c *Client) (*Order, error)
+		path  string
+		query string
+	}{
+		{
+			"TerminateImmediately",
+			func(c *Client) (*Order, error) { return c.TerminateOrder(context.Background(), "tv4", "123456", true) },
+			"/api/tv4/order/123456/terminate", "immediate=true",
+		},
+		{
+			"TerminateEndOfPeriod",
+			func(c *Client) (*Order, error) { return c.TerminateOrder(context.Background(), "tv4", "123456", false) },
+			"/api/tv4/order/123456/terminate", "immediate=false",
+		},
+		{
+			"CancelAutoRenew",
+			func(c *Client) (*Order, error) { return c.CancelAutoRenew(context.Background(), "tv4", "123456") },
+			"/api/tv4/order/123456/autorenew/cancel", "",
+		},
+		{
+			"Reactivate",
+			func(c *Client) (*Order, error) { return c.ReactivateOrder(context.Background(), "tv4", "123456") },
+			"/api/tv4/order/123456/reactivate", "",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.Method, http.MethodPost; got != want {
+					t.Errorf("r.Method = %q, want %q", got, want)
+				}
+
+				if got, want := r.URL.Path, tt.path; got != want {
+					t.Errorf("r.URL.Path = %q, want %q", got, want)
+				}
+
+				if got, want := r.URL.RawQuery, tt.query; got != want {
+					t.Errorf("r.URL.RawQuery = %q, want %q", got, want)
+				}
+
+				w.Write([]byte(`{"id":123456}`))
+			})
+			defer ts.Close()
+
+			order, err := tt.do(c)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := order.ID, "123456"; got != want {
+				t.Errorf("order.ID = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPatchOrder(t *testing.T) {
+	const orderJSON = `{"id":123456,"endDate":1000,"bigNumber":9007199254740993,"note":null%s}`
+
+	testServer := func(before, after string) (*httptest.Server, *Client, *[]string) {
+		var puts []string
+
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				if len(puts) == 0 {
+					w.Write([]byte(before))
+				} else {
+					w.Write([]byte(after))
+				}
+			case http.MethodPut:
+				b, _ := ioutil.ReadAll(r.Body)
+				puts = append(puts, string(b))
+				w.Write([]byte(`{"id":123456}`))
+			}
+		})
+
+		return ts, c, &puts
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		ts, c, puts := testServer(
+			fmt.Sprintf(orderJSON, ""),
+			`{"id":123456,"endDate":2000,"bigNumber":9007199254740993}`,
+		)
+		defer ts.Close()
+
+		res, err := c.PatchOrder(context.Background(), "tv4", "123456", map[string]interface{}{"endDate": 2000}, PatchOrderOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := *puts, []string{`{"bigNumber":9007199254740993,"endDate":2000,"id":123456}`}; !cmp.Equal(got, want) {
+			t.Errorf("puts = %q, want %q", got, want)
+		}
+
+		wantChanges := []FieldChange{
+			{Field: "endDate", Old: json.Number("1000"), New: json.Number("2000")},
+		}
+
+		if got, want := res.Changes, wantChanges; !cmp.Equal(got, want) {
+			t.Errorf("res.Changes = %v, want %v", got, want)
+		}
+
+		if got := res.Lost; len(got) != 0 {
+			t.Errorf("res.Lost = %q, want none", got)
+		}
+	})
+
+	t.Run("RefuseNestedObjects", func(t *testing.T) {
+		ts, c, puts := testServer(fmt.Sprintf(orderJSON, `,"product":{"id":1}`), "")
+		defer ts.Close()
+
+		_, err := c.PatchOrder(context.Background(), "tv4", "123456", map[string]interface{}{"endDate": 2000}, PatchOrderOptions{})
+
+		var fieldLossErr *FieldLossError
+
+		if !errors.As(err, &fieldLossErr) {
+			t.Fatalf("err = %v, want *FieldLossError", err)
+		}
+
+		if got, want := fieldLossErr.Fields, []string{"product"}; !cmp.Equal(got, want) {
+			t.Errorf("fieldLossErr.Fields = %q, want %q", got, want)
+		}
+
+		if got := len(*puts); got != 0 {
+			t.Errorf("got %d PUTs, want none", got)
+		}
+	})
+
+	t.Run("AllowNestedObjects", func(t *testing.T) {
+		ts, c, puts := testServer(
+			fmt.Sprintf(orderJSON, `,"product":{"id":1}`),
+			`{"id":123456,"endDate":2000,"bigNumber":9007199254740993,"product":{"id":1}}`,
+		)
+		defer ts.Close()
+
+		res, err := c.PatchOrder(context.Background(), "tv4", "123456", map[string]interface{}{"endDate": 2000}, PatchOrderOptions{AllowFieldLoss: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := len(*puts), 1; got != want {
+			t.Errorf("got %d PUTs, want %d", got, want)
+		}
+
+		if got := res.Lost; len(got) != 0 {
+			t.Errorf("res.Lost = %q, want none", got)
+		}
+	})
+
+	t.Run("FieldsLostAfterWrite", func(t *testing.T) {
+		ts, c, _ := testServer(fmt.Sprintf(orderJSON, ""), `{"id":123456,"endDate":2000}`)
+		defer ts.Close()
+
+		res, err := c.PatchOrder(context.Background(), "tv4", "123456", map[string]interface{}{"endDate": 2000}, PatchOrderOptions{})
+
+		var fieldLossErr *FieldLossError
+
+		if !errors.As(err, &fieldLossErr) {
+			t.Fatalf("err = %v, want *FieldLossError", err)
+		}
+
+		if got, want := res.Lost, []string{"bigNumber"}; !cmp.Equal(got, want) {
+			t.Errorf("res.Lost = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestSetOrderEndDates(t *testing.T) {
+	endDate := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				w.Write([]byte(`{"id":123456,"endDate":1000}`))
+			case http.MethodPut:
+				var body map[string]interface{}
+
+				json.NewDecoder(r.Body).Decode(&body)
+
+				if got, want := body["endDate"], float64(endDate.Unix()*1000); got != want {
+					t.Errorf(`body["endDate"] = %v, want %v`, got, want)
+				}
+
+				w.Write([]byte(`{"id":123456,"endDate":"2000-01-02T03:04:05Z"}`))
+			}
+		})
+		defer ts.Close()
+
+		order, err := c.SetOrderEndDates(context.Background(), "tv4", "123456", endDate)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := order.EndDate, endDate; !got.Equal(want) {
+			t.Errorf("order.EndDate = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("NestedObjects", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				t.Errorf("unexpected %s request", r.Method)
+			}
+
+			w.Write([]byte(`{"id":123456,"endDate":1000,"product":{"id":1}}`))
+		})
+		defer ts.Close()
+
+		_, err := c.SetOrderEndDates(context.Background(), "tv4", "123456", endDate)
+
+		var fieldLossErr *FieldLossError
+
+		if !errors.As(err, &fieldLossErr) {
+			t.Fatalf("err = %v, want *FieldLossError", err)
+		}
+
+		if got, want := fieldLossErr.Fields, []string{"product"}; !cmp.Equal(got, want) {
+			t.Errorf("fieldLossErr.Fields = %v, want %v", got, want)
+		}
+	})
+}
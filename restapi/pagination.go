@@ -0,0 +1,36 @@
+package restapi
+
+import "iter"
+
+const defaultPageSize = 50
+
+// pageFunc fetches the page starting at the given offset, returning the items
+// in the page and the total number of items, or -1 if the total is unknown.
+type pageFunc[T any] func(start int) ([]T, int, error)
+
+// paginate returns an iterator that lazily fetches pages of pageSize items
+// using fetch. Iteration stops after the first error.
+func paginate[T any](pageSize int, fetch pageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for start := 0; ; {
+			items, total, err := fetch(start)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			start += len(items)
+
+			if len(items) == 0 || (total >= 0 && start >= total) || (total < 0 && len(items) < pageSize) {
+				return
+			}
+		}
+	}
+}
@@ -15,8 +15,12 @@ type Platform struct {
 	Name string
 }
 
-// Platforms returns the list of available platforms.
-func (c *Client) Platforms(ctx context.Context) ([]Platform, error) {
+// Platforms returns the list of available platforms. Errors for unexpected
+// responses match ErrUnknown, whatever the status code.
+func (c *Client) Platforms(ctx context.Context) (_ []Platform, err error) {
+	ctx, op := c.startOperation(ctx, "Platforms", "")
+	defer op.end(&err)
+
 	path := "/api/admin/platforms"
 
 	resp, err := c.get(ctx, path, url.Values{})
@@ -29,7 +33,7 @@ func (c *Client) Platforms(ctx context.Context) ([]Platform, error) {
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, ErrUnknown
+		return nil, newUnknownAPIError(resp)
 	}
 
 	return parsePlatforms(resp.Body)
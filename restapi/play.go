@@ -0,0 +1,201 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// PlayOptions holds the options of Play. Zero values are ignored.
+type PlayOptions struct {
+	// Protocol limits the playback items to a protocol, such as HLS or DASH.
+	Protocol string
+
+	// DeviceType is the type of device the playback is requested for.
+	DeviceType string
+}
+
+// Playback is the response of the play endpoint.
+type Playback struct {
+	AssetID   string
+	Title     string
+	Live      bool
+	Items     []PlaybackItem
+	Subtitles []PlaybackSubtitle
+	AdMarkers []AdMarker
+}
+
+// PlaybackItem is a stream an asset can be played from.
+type PlaybackItem struct {
+	URL        string
+	Protocol   string
+	MediaType  string
+	Bitrate    int
+	DRM        string
+	LicenseURL string
+}
+
+// PlaybackSubtitle is a subtitle track of a playback.
+type PlaybackSubtitle struct {
+	URL    string
+	Lang   string
+	Format string
+}
+
+// AdMarker is a position in a playback where ads can be inserted.
+type AdMarker struct {
+	Offset time.Duration
+	Type   string
+}
+
+// Play returns the playback of an asset, as the end-user play endpoint
+// returns it.
+func (c *Client) Play(ctx context.Context, platform, assetID string, opts PlayOptions) (_ *Playback, err error) {
+	ctx, op := c.startOperation(ctx, "Play", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	query := url.Values{}
+
+	if opts.Protocol != "" {
+		query.Set("protocol", opts.Protocol)
+	}
+
+	if opts.DeviceType != "" {
+		query.Set("deviceType", opts.DeviceType)
+	}
+
+	resp, err := c.get(ctx, c.assetPath(platform, assetID)+"/play", query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parsePlayback(resp.Body)
+}
+
+// BestItem returns the playback item to use, given the protocols in order of
+// preference. Among the items of the most preferred available protocol, the
+// one with the highest bitrate not above maxBitrate is returned, where a
+// maxBitrate of 0 means no limit. All protocols are accepted if none are
+// given. The second return value is false if no item matches.
+func (p *Playback) BestItem(protocols []string, maxBitrate int) (PlaybackItem, bool) {
+	rank := func(item PlaybackItem) int {
+		if len(protocols) == 0 {
+			return 0
+		}
+
+		return slices.Index(protocols, item.Protocol)
+	}
+
+	var (
+		best  PlaybackItem
+		found bool
+	)
+
+	for _, item := range p.Items {
+		r := rank(item)
+
+		if r < 0 || (maxBitrate > 0 && item.Bitrate > maxBitrate) {
+			continue
+		}
+
+		if !found || r < rank(best) || (r == rank(best) && item.Bitrate > best.Bitrate) {
+			best, found = item, true
+		}
+	}
+
+	return best, found
+}
+
+func parsePlayback(r io.Reader) (*Playback, error) {
+	var resp struct {
+		Playback struct {
+			AssetID int    `json:"assetId"`
+			Title   string `json:"title"`
+			Live    bool   `json:"live"`
+			Items   struct {
+				Item []struct {
+					URL         string `json:"url"`
+					MediaFormat string `json:"mediaFormat"`
+					MimeType    string `json:"mimeType"`
+					Bitrate     int    `json:"bitrate"`
+					License     *struct {
+						Type string `json:"type"`
+						URI  string `json:"uri"`
+					} `json:"license"`
+				} `json:"item"`
+			} `json:"items"`
+			Subtitles []struct {
+				URL    string `json:"url"`
+				Locale string `json:"locale"`
+				Format string `json:"format"`
+			} `json:"subtitles"`
+			CuePoints []struct {
+				Offset float64 `json:"offset"`
+				Type   string  `json:"type"`
+			} `json:"cuePoints"`
+		} `json:"playback"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	pb := resp.Playback
+
+	p := &Playback{
+		AssetID: strconv.Itoa(pb.AssetID),
+		Title:   pb.Title,
+		Live:    pb.Live,
+	}
+
+	for _, item := range pb.Items.Item {
+		pi := PlaybackItem{
+			URL:       item.URL,
+			Protocol:  item.MediaFormat,
+			MediaType: item.MimeType,
+			Bitrate:   item.Bitrate,
+		}
+
+		if item.License != nil {
+			pi.DRM = item.License.Type
+			pi.LicenseURL = item.License.URI
+		}
+
+		p.Items = append(p.Items, pi)
+	}
+
+	for _, s := range pb.Subtitles {
+		p.Subtitles = append(p.Subtitles, PlaybackSubtitle{
+			URL:    s.URL,
+			Lang:   s.Locale,
+			Format: s.Format,
+		})
+	}
+
+	for _, cp := range pb.CuePoints {
+		p.AdMarkers = append(p.AdMarkers, AdMarker{
+			Offset: time.Duration(cp.Offset * float64(time.Second)),
+			Type:   cp.Type,
+		})
+	}
+
+	return p, nil
+}
@@ -0,0 +1,114 @@
+package restapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlay(t *testing.T) {
+	t.Run("InvalidAssetID", func(t *testing.T) {
+		c := &Client{}
+
+		if _, err := c.Play(context.Background(), "tv4", "invalid", PlayOptions{}); err != ErrInvalidAssetID {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			if got, want := r.URL.Path, "/api/tv4/asset/10006/play"; got != want {
+				t.Errorf("r.URL.Path = %q, want %q", got, want)
+			}
+
+			if got, want := r.URL.Query().Get("protocol"), "DASH"; got != want {
+				t.Errorf("protocol = %q, want %q", got, want)
+			}
+
+			w.Write([]byte(`{
+				"playback": {
+					"assetId": 10006,
+					"title": "title foo",
+					"items": {
+						"item": [
+							{
+								"url": "https://example.com/foo.mpd",
+								"mediaFormat": "DASH",
+								"mimeType": "application/dash+xml",
+								"bitrate": 4000,
+								"license": {"type": "WIDEVINE", "uri": "https://example.com/license"}
+							}
+						]
+					},
+					"subtitles": [{"url": "https://example.com/sv.vtt", "locale": "sv", "format": "webvtt"}],
+					"cuePoints": [{"offset": 90.5, "type": "midroll"}]
+				}
+			}`))
+		})
+		defer ts.Close()
+
+		playback, err := c.Play(context.Background(), "tv4", "10006", PlayOptions{Protocol: "DASH"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := &Playback{
+			AssetID: "10006",
+			Title:   "title foo",
+			Items: []PlaybackItem{{
+				URL:        "https://example.com/foo.mpd",
+				Protocol:   "DASH",
+				MediaType:  "application/dash+xml",
+				Bitrate:    4000,
+				DRM:        "WIDEVINE",
+				LicenseURL: "https://example.com/license",
+			}},
+			Subtitles: []PlaybackSubtitle{{URL: "https://example.com/sv.vtt", Lang: "sv", Format: "webvtt"}},
+			AdMarkers: []AdMarker{{Offset: 90500 * time.Millisecond, Type: "midroll"}},
+		}
+
+		if !cmp.Equal(playback, want) {
+			t.Errorf("playback mismatch (-got +want):\n%s", cmp.Diff(playback, want))
+		}
+	})
+}
+
+func TestPlaybackBestItem(t *testing.T) {
+	p := &Playback{
+		Items: []PlaybackItem{
+			{URL: "hls-low", Protocol: "HLS", Bitrate: 1000},
+			{URL: "hls-high", Protocol: "HLS", Bitrate: 6000},
+			{URL: "dash-low", Protocol: "DASH", Bitrate: 1000},
+			{URL: "dash-mid", Protocol: "DASH", Bitrate: 3000},
+		},
+	}
+
+	for _, tt := range []struct {
+		name       string
+		protocols  []string
+		maxBitrate int
+		want       string
+	}{
+		{"any_protocol", nil, 0, "hls-high"},
+		{"preferred_protocol", []string{"DASH", "HLS"}, 0, "dash-mid"},
+		{"max_bitrate", []string{"HLS"}, 5000, "hls-low"},
+		{"fallback_protocol", []string{"MSS", "HLS"}, 0, "hls-high"},
+		{"fallback_for_bitrate", []string{"DASH", "HLS"}, 500, ""},
+		{"no_match", []string{"MSS"}, 0, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			item, ok := p.BestItem(tt.protocols, tt.maxBitrate)
+
+			if got, want := ok, tt.want != ""; got != want {
+				t.Fatalf("ok = %v, want %v", got, want)
+			}
+
+			if got, want := item.URL, tt.want; got != want {
+				t.Errorf("item.URL = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ProductGroup is a group of products, such as a subscription package.
+type ProductGroup struct {
+	ID          string
+	Name        string
+	Description string
+	SortIndex   int
+}
+
+// Product is a product in a product group.
+type Product struct {
+	ID             string
+	ProductGroupID string
+	Name           string
+	Description    string
+	Enabled        bool
+}
+
+// ProductPayment is a way of paying for a product, which is what an order is
+// created for.
+type ProductPayment struct {
+	ID                string
+	ProductID         string
+	Description       string
+	Price             float64
+	Currency          string
+	Period            string
+	TrialPeriod       string
+	TrialPrice        float64
+	AutoRenew         bool
+	PaymentProviderID string
+	Enabled           bool
+}
+
+// ProductGroups returns the product groups of a platform.
+func (c *Client) ProductGroups(ctx context.Context, platform string) (_ []ProductGroup, err error) {
+	ctx, op := c.startOperation(ctx, "ProductGroups", platform)
+	defer op.end(&err)
+
+	resp, err := c.get(ctx, fmt.Sprintf("/api/%s/productgroup", platform), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseProductGroups(resp.Body)
+}
+
+// Products returns the products in a product group.
+func (c *Client) Products(ctx context.Context, platform, productGroupID string) (_ []Product, err error) {
+	ctx, op := c.startOperation(ctx, "Products", platform)
+	defer op.end(&err)
+
+	path := fmt.Sprintf("/api/%s/productgroup/%s/products", platform, productGroupID)
+
+	resp, err := c.get(ctx, path, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseProducts(resp.Body)
+}
+
+// ProductPayments returns the product payments of a product.
+func (c *Client) ProductPayments(ctx context.Context, platform, productID string) (_ []ProductPayment, err error) {
+	ctx, op := c.startOperation(ctx, "ProductPayments", platform)
+	defer op.end(&err)
+
+	path := fmt.Sprintf("/api/%s/product/%s/productPayments", platform, productID)
+
+	resp, err := c.get(ctx, path, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseProductPayments(resp.Body)
+}
+
+func parseProductGroups(r io.Reader) ([]ProductGroup, error) {
+	var resp []struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		SortIndex   int    `json:"sortIndex"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	groups := make([]ProductGroup, 0, len(resp))
+
+	for n := range resp {
+		groups = append(groups, ProductGroup{
+			ID:          strconv.Itoa(resp[n].ID),
+			Name:        resp[n].Name,
+			Description: resp[n].Description,
+			SortIndex:   resp[n].SortIndex,
+		})
+	}
+
+	return groups, nil
+}
+
+func parseProducts(r io.Reader) ([]Product, error) {
+	var resp []struct {
+		ID             int    `json:"id"`
+		ProductGroupID int    `json:"productGroupId"`
+		Name           string `json:"name"`
+		Description    string `json:"description"`
+		Enabled        bool   `json:"enabled"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	products := make([]Product, 0, len(resp))
+
+	for n := range resp {
+		products = append(products, Product{
+			ID:             strconv.Itoa(resp[n].ID),
+			ProductGroupID: strconv.Itoa(resp[n].ProductGroupID),
+			Name:           resp[n].Name,
+			Description:    resp[n].Description,
+			Enabled:        resp[n].Enabled,
+		})
+	}
+
+	return products, nil
+}
+
+func parseProductPayments(r io.Reader) ([]ProductPayment, error) {
+	var resp []struct {
+		ID                int     `json:"id"`
+		ProductID         int     `json:"productId"`
+		Description       string  `json:"description"`
+		Price             float64 `json:"price"`
+		Currency          string  `json:"currency"`
+		Period            string  `json:"period"`
+		InitPeriod        string  `json:"initPeriod"`
+		InitPrice         float64 `json:"initPrice"`
+		AutoRenew         bool    `json:"autoRenew"`
+		PaymentProviderID int     `json:"paymentProviderId"`
+		Enabled           bool    `json:"enabled"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	payments := make([]ProductPayment, 0, len(resp))
+
+	for n := range resp {
+		payments = append(payments, ProductPayment{
+			ID:                strconv.Itoa(resp[n].ID),
+			ProductID:         strconv.Itoa(resp[n].ProductID),
+			Description:       resp[n].Description,
+			Price:             resp[n].Price,
+			Currency:          resp[n].Currency,
+			Period:            resp[n].Period,
+			TrialPeriod:       resp[n].InitPeriod,
+			TrialPrice:        resp[n].InitPrice,
+			AutoRenew:         resp[n].AutoRenew,
+			PaymentProviderID: strconv.Itoa(resp[n].PaymentProviderID),
+			Enabled:           resp[n].Enabled,
+		})
+	}
+
+	return payments, nil
+}
@@ -0,0 +1,72 @@
+package restapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProductCatalog(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tv4/productgroup":
+			w.Write([]byte(`[{"id":12,"name":"foo-group","sortIndex":1}]`))
+		case "/api/tv4/productgroup/12/products":
+			w.Write([]byte(`[{"id":34,"productGroupId":12,"name":"foo-product","enabled":true}]`))
+		case "/api/tv4/product/34/productPayments":
+			w.Write([]byte(`[{"id":56,"productId":34,"description":"foo-payment","price":99.5,"currency":"SEK","period":"P1M","initPeriod":"P14D","autoRenew":true,"paymentProviderId":3,"enabled":true}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer ts.Close()
+
+	groups, err := c.ProductGroups(context.Background(), "tv4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := groups, []ProductGroup{{ID: "12", Name: "foo-group", SortIndex: 1}}; !cmp.Equal(got, want) {
+		t.Errorf("groups = %+v, want %+v", got, want)
+	}
+
+	products, err := c.Products(context.Background(), "tv4", "12")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := products, []Product{{ID: "34", ProductGroupID: "12", Name: "foo-product", Enabled: true}}; !cmp.Equal(got, want) {
+		t.Errorf("products = %+v, want %+v", got, want)
+	}
+
+	payments, err := c.ProductPayments(context.Background(), "tv4", "34")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantPayments := []ProductPayment{{
+		ID:                "56",
+		ProductID:         "34",
+		Description:       "foo-payment",
+		Price:             99.5,
+		Currency:          "SEK",
+		Period:            "P1M",
+		TrialPeriod:       "P14D",
+		AutoRenew:         true,
+		PaymentProviderID: "3",
+		Enabled:           true,
+	}}
+
+	if got, want := payments, wantPayments; !cmp.Equal(got, want) {
+		t.Errorf("payments = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseProductGroups(t *testing.T) {
+	if _, err := parseProductGroups(strings.NewReader("not-json")); err == nil {
+		t.Fatal("err is nil")
+	}
+}
@@ -0,0 +1,251 @@
+package restapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// PublishingItem is a window during which an asset is published on a
+// platform.
+type PublishingItem struct {
+	ID             string
+	AssetID        string
+	Platform       string
+	Publish        time.Time
+	Expire         time.Time
+	GeoRegions     []string
+	ProductGroupID string
+}
+
+// PublishingItems returns the publishing items of an asset, for all platforms.
+func (c *Client) PublishingItems(ctx context.Context, assetID string) (_ []PublishingItem, err error) {
+	ctx, op := c.startOperation(ctx, "PublishingItems", "", AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	resp, err := c.get(ctx, c.publishingItemsPath(assetID), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parsePublishingItems(resp.Body)
+}
+
+// CreatePublishingItem creates a publishing window for the asset and platform
+// of the given item. The ID of the given item is ignored.
+func (c *Client) CreatePublishingItem(ctx context.Context, item PublishingItem) (_ *PublishingItem, err error) {
+	ctx, op := c.startOperation(ctx, "CreatePublishingItem", item.Platform, AttributeAssetID.String(item.AssetID))
+	defer op.end(&err)
+
+	item.ID = ""
+
+	body, err := marshalPublishingItem(item)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.post(ctx, c.publishingItemsPath(item.AssetID), url.Values{}, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, newAPIError(resp)
+	}
+
+	return parsePublishingItem(resp.Body)
+}
+
+// UpdatePublishingItem replaces the publishing item with the given item,
+// identified by its asset ID and ID.
+func (c *Client) UpdatePublishingItem(ctx context.Context, item PublishingItem) (_ *PublishingItem, err error) {
+	ctx, op := c.startOperation(ctx, "UpdatePublishingItem", item.Platform, AttributeAssetID.String(item.AssetID))
+	defer op.end(&err)
+
+	body, err := marshalPublishingItem(item)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.put(ctx, c.publishingItemsPath(item.AssetID)+"/"+item.ID, url.Values{}, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parsePublishingItem(resp.Body)
+}
+
+// DeletePublishingItem deletes a publishing item of an asset.
+func (c *Client) DeletePublishingItem(ctx context.Context, assetID, itemID string) (err error) {
+	ctx, op := c.startOperation(ctx, "DeletePublishingItem", "", AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	resp, err := c.delete(ctx, c.publishingItemsPath(assetID)+"/"+itemID, url.Values{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+// Unpublish deletes all publishing items of an asset on the given platform.
+func (c *Client) Unpublish(ctx context.Context, platform, assetID string) (err error) {
+	ctx, op := c.startOperation(ctx, "Unpublish", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	items, err := c.PublishingItems(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item.Platform != platform {
+			continue
+		}
+
+		if err := c.DeletePublishingItem(ctx, assetID, item.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) publishingItemsPath(assetID string) string {
+	return fmt.Sprintf("/api/admin/asset/%s/publishingitems", assetID)
+}
+
+type vimondPublishingItem struct {
+	ID             int        `json:"id,omitempty"`
+	AssetID        int        `json:"assetId"`
+	Platform       string     `json:"platform"`
+	Publish        *time.Time `json:"publish,omitempty"`
+	Expire         *time.Time `json:"expire,omitempty"`
+	GeoRegions     []string   `json:"geoRegions,omitempty"`
+	ProductGroupID int        `json:"productGroupId,omitempty"`
+}
+
+func (vpi *vimondPublishingItem) publishingItem() PublishingItem {
+	pi := PublishingItem{
+		ID:         strconv.Itoa(vpi.ID),
+		AssetID:    strconv.Itoa(vpi.AssetID),
+		Platform:   vpi.Platform,
+		GeoRegions: vpi.GeoRegions,
+	}
+
+	if vpi.Publish != nil {
+		pi.Publish = *vpi.Publish
+	}
+
+	if vpi.Expire != nil {
+		pi.Expire = *vpi.Expire
+	}
+
+	if vpi.ProductGroupID != 0 {
+		pi.ProductGroupID = strconv.Itoa(vpi.ProductGroupID)
+	}
+
+	return pi
+}
+
+func marshalPublishingItem(pi PublishingItem) ([]byte, error) {
+	vpi := vimondPublishingItem{
+		Platform:   pi.Platform,
+		GeoRegions: pi.GeoRegions,
+	}
+
+	if !pi.Publish.IsZero() {
+		vpi.Publish = &pi.Publish
+	}
+
+	if !pi.Expire.IsZero() {
+		vpi.Expire = &pi.Expire
+	}
+
+	var err error
+
+	if vpi.AssetID, err = strconv.Atoi(pi.AssetID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	if pi.ID != "" {
+		if vpi.ID, err = strconv.Atoi(pi.ID); err != nil {
+			return nil, fmt.Errorf("vimond/restapi: invalid publishing item id %q", pi.ID)
+		}
+	}
+
+	if pi.ProductGroupID != "" {
+		if vpi.ProductGroupID, err = strconv.Atoi(pi.ProductGroupID); err != nil {
+			return nil, fmt.Errorf("vimond/restapi: invalid product group id %q", pi.ProductGroupID)
+		}
+	}
+
+	return json.Marshal(&vpi)
+}
+
+func parsePublishingItem(r io.Reader) (*PublishingItem, error) {
+	var vpi vimondPublishingItem
+
+	if err := json.NewDecoder(r).Decode(&vpi); err != nil {
+		return nil, err
+	}
+
+	pi := vpi.publishingItem()
+
+	return &pi, nil
+}
+
+func parsePublishingItems(r io.Reader) ([]PublishingItem, error) {
+	var resp []vimondPublishingItem
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	items := make([]PublishingItem, 0, len(resp))
+
+	for n := range resp {
+		items = append(items, resp[n].publishingItem())
+	}
+
+	return items, nil
+}
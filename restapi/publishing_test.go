@@ -0,0 +1,124 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPublishingItems(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/api/admin/asset/10006/publishingitems"; got != want {
+			t.Errorf("r.URL.Path = %q, want %q", got, want)
+		}
+
+		w.Write([]byte(`[{"id":1,"assetId":10006,"platform":"tv4","publish":"2000-01-02T00:00:00Z","expire":"2000-02-02T00:00:00Z","geoRegions":["se"],"productGroupId":12}]`))
+	})
+	defer ts.Close()
+
+	items, err := c.PublishingItems(context.Background(), "10006")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []PublishingItem{{
+		ID:             "1",
+		AssetID:        "10006",
+		Platform:       "tv4",
+		Publish:        time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+		Expire:         time.Date(2000, 2, 2, 0, 0, 0, 0, time.UTC),
+		GeoRegions:     []string{"se"},
+		ProductGroupID: "12",
+	}}
+
+	if !cmp.Equal(items, want) {
+		t.Errorf("items mismatch (-got +want):\n%s", cmp.Diff(items, want))
+	}
+}
+
+func TestCreateAndUpdatePublishingItem(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.Method {
+		case http.MethodPost:
+			if got, want := r.URL.Path, "/api/admin/asset/10006/publishingitems"; got != want {
+				t.Errorf("r.URL.Path = %q, want %q", got, want)
+			}
+
+			if _, ok := body["id"]; ok {
+				t.Errorf("body has id %v", body["id"])
+			}
+
+			body["id"] = 1
+		case http.MethodPut:
+			if got, want := r.URL.Path, "/api/admin/asset/10006/publishingitems/1"; got != want {
+				t.Errorf("r.URL.Path = %q, want %q", got, want)
+			}
+		}
+
+		json.NewEncoder(w).Encode(body)
+	})
+	defer ts.Close()
+
+	item := PublishingItem{
+		ID:       "123",
+		AssetID:  "10006",
+		Platform: "tv4",
+		Publish:  time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	created, err := c.CreatePublishingItem(context.Background(), item)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := created.ID, "1"; got != want {
+		t.Errorf("created.ID = %q, want %q", got, want)
+	}
+
+	created.Expire = time.Date(2000, 2, 2, 0, 0, 0, 0, time.UTC)
+
+	updated, err := c.UpdatePublishingItem(context.Background(), *created)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := updated.Expire, created.Expire; !got.Equal(want) {
+		t.Errorf("updated.Expire = %v, want %v", got, want)
+	}
+}
+
+func TestUnpublish(t *testing.T) {
+	var deleted []string
+
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`[{"id":1,"assetId":10006,"platform":"tv4"},{"id":2,"assetId":10006,"platform":"foo"},{"id":3,"assetId":10006,"platform":"tv4"}]`))
+		case http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	defer ts.Close()
+
+	if err := c.Unpublish(context.Background(), "tv4", "10006"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"/api/admin/asset/10006/publishingitems/1",
+		"/api/admin/asset/10006/publishingitems/3",
+	}
+
+	if !cmp.Equal(deleted, want) {
+		t.Errorf("deleted = %q, want %q", deleted, want)
+	}
+}
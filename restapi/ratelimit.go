@@ -0,0 +1,220 @@
+package restapi
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultThrottleDelay is how long requests are held back after a 429
+	// response without a Retry-After header.
+	defaultThrottleDelay = time.Second
+
+	// maxThrottleDelay caps how long requests are held back after a 429
+	// response by a client without a retry policy.
+	maxThrottleDelay = 30 * time.Second
+)
+
+// RateLimit limits the requests sent by the *client to rps requests per
+// second, allowing bursts of up to burst requests. Requests block until they
+// are allowed or their context is done. A rate of zero or less means no
+// limit.
+//
+// Requests are held back after a 429 response, for as long as its
+// Retry-After header asks, whether or not a rate limit is set. The delay is
+// capped by the MaxBackoff of the retry policy, or by 30 seconds for a
+// client without one.
+func RateLimit(rps float64, burst int) func(*Client) {
+	return func(c *Client) {
+		if c.rateLimiter == nil {
+			c.rateLimiter = &rateLimiter{}
+		}
+
+		c.rateLimiter.fallback = newTokenBucket(rps, burst)
+	}
+}
+
+// PlatformRateLimit limits the requests sent by the *client for the given
+// platform, overriding the limit set by RateLimit for that platform. A rate
+// of zero or less means no limit for the platform.
+func PlatformRateLimit(platform string, rps float64, burst int) func(*Client) {
+	return func(c *Client) {
+		if c.rateLimiter == nil {
+			c.rateLimiter = &rateLimiter{}
+		}
+
+		if c.rateLimiter.platforms == nil {
+			c.rateLimiter.platforms = map[string]*tokenBucket{}
+		}
+
+		c.rateLimiter.platforms[platform] = newTokenBucket(rps, burst)
+	}
+}
+
+// rateLimiter holds a token bucket per platform, and a fallback bucket used
+// for the remaining requests. Platforms without a bucket get an unlimited
+// one when throttled, so that 429 responses are honoured for them too.
+type rateLimiter struct {
+	fallback  *tokenBucket
+	platforms map[string]*tokenBucket
+
+	mu        sync.Mutex
+	throttled map[string]*tokenBucket
+}
+
+// bucket returns the token bucket for the request path, or nil if the path
+// is neither rate limited nor throttled.
+func (rl *rateLimiter) bucket(path string) *tokenBucket {
+	if rl == nil {
+		return nil
+	}
+
+	platform := pathPlatform(path)
+
+	if b, ok := rl.platforms[platform]; ok {
+		return b
+	}
+
+	if rl.fallback != nil {
+		return rl.fallback
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	return rl.throttled[platform]
+}
+
+// wait blocks until a request to path is allowed.
+func (rl *rateLimiter) wait(ctx context.Context, path string) error {
+	return rl.bucket(path).wait(ctx)
+}
+
+// throttle holds back requests to path for d, used when Vimond responds
+// with 429 Too Many Requests.
+func (rl *rateLimiter) throttle(path string, d time.Duration) {
+	if rl == nil {
+		return
+	}
+
+	b := rl.bucket(path)
+
+	if b == nil {
+		rl.mu.Lock()
+
+		if rl.throttled == nil {
+			rl.throttled = map[string]*tokenBucket{}
+		}
+
+		platform := pathPlatform(path)
+
+		if b = rl.throttled[platform]; b == nil {
+			b = newTokenBucket(0, 0)
+			rl.throttled[platform] = b
+		}
+
+		rl.mu.Unlock()
+	}
+
+	b.pause(time.Now().Add(d))
+}
+
+// pathPlatform returns the platform of API paths on the form
+// /api/{platform}/...
+func pathPlatform(path string) string {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+
+	if len(parts) < 2 || parts[0] != "api" {
+		return ""
+	}
+
+	return parts[1]
+}
+
+type tokenBucket struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// reserve takes a token from the bucket and returns how long the caller has
+// to wait before using it.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.last.IsZero() {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+
+	var d time.Duration
+
+	// A bucket without a rate only holds back requests while paused.
+	if b.rate > 0 {
+		b.last = now
+		b.tokens--
+
+		if b.tokens < 0 {
+			d = time.Duration(-b.tokens / b.rate * float64(time.Second))
+		}
+	}
+
+	if p := b.pausedUntil.Sub(now); p > d {
+		d = p
+	}
+
+	return d
+}
+
+// cancel returns a reserved token to the bucket.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rate > 0 {
+		b.tokens = min(b.burst, b.tokens+1)
+	}
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return ctx.Err()
+	}
+
+	if err := sleep(ctx, b.reserve(time.Now())); err != nil {
+		b.cancel()
+		return err
+	}
+
+	return nil
+}
+
+func (b *tokenBucket) pause(until time.Time) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
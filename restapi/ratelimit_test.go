@@ -0,0 +1,192 @@
+package restapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Run("Options", func(t *testing.T) {
+		c := NewClient(RateLimit(10, 5), PlatformRateLimit("foo", 1, 1))
+
+		if got, want := c.rateLimiter.bucket("/api/bar/asset/123"), c.rateLimiter.fallback; got != want {
+			t.Errorf("bucket for bar = %p, want fallback %p", got, want)
+		}
+
+		if got, want := c.rateLimiter.bucket("/api/foo/asset/123"), c.rateLimiter.platforms["foo"]; got != want {
+			t.Errorf("bucket for foo = %p, want %p", got, want)
+		}
+
+		if got := c.rateLimiter.platforms["foo"].rate; got != 1 {
+			t.Errorf("foo rate = %v, want 1", got)
+		}
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		var rl *rateLimiter
+
+		if err := rl.wait(context.Background(), "/api/foo/asset/123"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		rl.throttle("/api/foo/asset/123", time.Second)
+	})
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		c := NewClient(RateLimit(0.001, 1))
+
+		if err := c.rateLimiter.wait(context.Background(), "/foo"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if err := c.rateLimiter.wait(ctx, "/foo"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("TooManyRequests", func(t *testing.T) {
+		var attempts int
+
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+
+			if attempts == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			w.Write([]byte(`[]`))
+		}, RateLimit(1000, 10), Retry(RetryPolicy{MaxAttempts: 2}))
+		defer ts.Close()
+
+		if _, err := c.Platforms(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := attempts, 2; got != want {
+			t.Errorf("attempts = %d, want %d", got, want)
+		}
+
+		if c.rateLimiter.fallback.pausedUntil.IsZero() {
+			t.Errorf("rate limiter was not paused")
+		}
+	})
+
+	t.Run("TooManyRequestsWithoutRateLimit", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+		defer ts.Close()
+
+		if _, err := c.Platforms(context.Background()); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if err := c.rateLimiter.wait(ctx, "/api/admin/platforms"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+		}
+
+		if err := c.rateLimiter.wait(context.Background(), "/api/tv4/asset/123"); err != nil {
+			t.Fatalf("other platform: unexpected error: %v", err)
+		}
+	})
+
+	t.Run("RetryAfterCappedByMaxBackoff", func(t *testing.T) {
+		var attempts int
+
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			if attempts++; attempts == 1 {
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			w.Write([]byte(`[]`))
+		}, RateLimit(1000, 10), Retry(RetryPolicy{MaxAttempts: 2, MaxBackoff: 10 * time.Millisecond}))
+		defer ts.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		start := time.Now()
+
+		for i := 0; i < 2; i++ {
+			if _, err := c.Platforms(ctx); err != nil {
+				t.Fatalf("request %d: unexpected error: %v", i, err)
+			}
+		}
+
+		if got, max := time.Since(start), 500*time.Millisecond; got > max {
+			t.Errorf("requests took %v, want at most %v", got, max)
+		}
+
+		if got, want := attempts, 3; got != want {
+			t.Errorf("attempts = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("ZeroRate", func(t *testing.T) {
+		c := NewClient(RateLimit(0, 1))
+
+		for i := 0; i < 10; i++ {
+			if d := c.rateLimiter.fallback.reserve(time.Now()); d != 0 {
+				t.Fatalf("reserve %d = %v, want 0", i, d)
+			}
+		}
+	})
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	b := newTokenBucket(2, 2)
+
+	for _, tt := range []struct {
+		offset time.Duration
+		want   time.Duration
+	}{
+		{0, 0},
+		{0, 0},
+		{0, 500 * time.Millisecond},
+		{0, time.Second},
+		{2 * time.Second, 0},
+	} {
+		if got := b.reserve(now.Add(tt.offset)); got != tt.want {
+			t.Fatalf("b.reserve(now + %v) = %v, want %v", tt.offset, got, tt.want)
+		}
+	}
+
+	b.pause(now.Add(5 * time.Second))
+
+	if got, want := b.reserve(now.Add(3*time.Second)), 2*time.Second; got != want {
+		t.Fatalf("b.reserve after pause = %v, want %v", got, want)
+	}
+}
+
+func TestPathPlatform(t *testing.T) {
+	for _, tt := range []struct {
+		path string
+		want string
+	}{
+		{"", ""},
+		{"/foo", ""},
+		{"/foo/bar", ""},
+		{"/api/tv4/asset/123", "tv4"},
+		{"/api/admin/platforms", "admin"},
+	} {
+		if got := pathPlatform(tt.path); got != tt.want {
+			t.Errorf("pathPlatform(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package restapi
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. GET requests, and
+// PUT requests if RetryPUT is set, are retried on network errors and on 429,
+// 502, 503 and 504 responses. Other requests, including DELETE, are never
+// retried, since a repeated DELETE fails with 404 if the first one succeeded.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+
+	// RetryPUT makes PUT requests retried as well. Only set it if the PUT
+	// requests made with the client are safe to repeat, such as full
+	// replacements of assets or metadata.
+	RetryPUT bool
+
+	// MinBackoff is the delay before the first retry. The delay doubles for
+	// every subsequent retry, with jitter applied.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts, including delays
+	// requested by a Retry-After header, and how long requests are held
+	// back after a 429 response.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used for the fields left unset in the RetryPolicy
+// given to Retry.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  250 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+}
+
+// Retry makes the *client retry requests according to the given policy
+func Retry(p RetryPolicy) func(*Client) {
+	return func(c *Client) {
+		if p.MaxAttempts <= 0 {
+			p.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+		}
+
+		if p.MinBackoff <= 0 {
+			p.MinBackoff = DefaultRetryPolicy.MinBackoff
+		}
+
+		if p.MaxBackoff <= 0 {
+			p.MaxBackoff = DefaultRetryPolicy.MaxBackoff
+		}
+
+		c.retryPolicy = &p
+	}
+}
+
+// attempts returns the number of attempts allowed for a request with the
+// given method.
+func (p *RetryPolicy) attempts(method string) int {
+	if p == nil {
+		return 1
+	}
+
+	switch method {
+	case http.MethodGet:
+		return p.MaxAttempts
+	case http.MethodPut:
+		if p.RetryPUT {
+			return p.MaxAttempts
+		}
+
+		return 1
+	default:
+		return 1
+	}
+}
+
+// backoff returns the delay before the retry following the given attempt.
+// A Retry-After header in resp takes precedence over the exponential backoff.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return min(d, p.MaxBackoff)
+		}
+	}
+
+	d := p.MinBackoff << (attempt - 1)
+	if d <= 0 || d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	// Equal jitter: half of the delay is fixed, the other half random.
+	return d/2 + rand.N(d/2+1)
+}
+
+// throttleDelay returns how long requests are held back after the 429
+// response resp, capped by MaxBackoff, or by maxThrottleDelay for a nil
+// policy.
+func (p *RetryPolicy) throttleDelay(resp *http.Response) time.Duration {
+	d, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		d = defaultThrottleDelay
+	}
+
+	if p == nil {
+		return min(d, maxThrottleDelay)
+	}
+
+	return min(d, p.MaxBackoff)
+}
+
+// shouldRetry reports whether a request that resulted in resp and err is
+// worth retrying.
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date.
+func retryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// sleep waits for d, returning early with the context error if ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
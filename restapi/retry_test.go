@@ -0,0 +1,186 @@
+package restapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	t.Run("GetSucceedsAfterRetries", func(t *testing.T) {
+		var attempts int
+
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+
+			if r.Header.Get("Authorization") == "" || r.Header.Get("Date") == "" {
+				t.Errorf("attempt %d is not signed", attempts)
+			}
+
+			if attempts < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			w.Write([]byte(`[{"id":1,"name":"foo"}]`))
+		}, Retry(policy), Credentials("foo-key", "foo-secret"))
+		defer ts.Close()
+
+		platforms, err := c.Platforms(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := attempts, 3; got != want {
+			t.Errorf("attempts = %d, want %d", got, want)
+		}
+
+		if got, want := len(platforms), 1; got != want {
+			t.Errorf("len(platforms) = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("GetGivesUp", func(t *testing.T) {
+		var attempts int
+
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusBadGateway)
+		}, Retry(policy))
+		defer ts.Close()
+
+		_, err := c.Platforms(context.Background())
+
+		var apiErr *APIError
+
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+			t.Fatalf("err = %v, want *APIError with status %d", err, http.StatusBadGateway)
+		}
+
+		if got, want := attempts, policy.MaxAttempts; got != want {
+			t.Errorf("attempts = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("PutResendsBody", func(t *testing.T) {
+		var bodies [][]byte
+
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, b)
+
+			if len(bodies) < 2 {
+				w.WriteHeader(http.StatusGatewayTimeout)
+			}
+		}, Retry(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, RetryPUT: true}))
+		defer ts.Close()
+
+		resp, err := c.put(context.Background(), "/foo", nil, bytes.NewReader([]byte("foo-body")))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if got, want := len(bodies), 2; got != want {
+			t.Fatalf("got %d attempts, want %d", got, want)
+		}
+
+		for n, b := range bodies {
+			if got, want := string(b), "foo-body"; got != want {
+				t.Errorf("bodies[%d] = %q, want %q", n, got, want)
+			}
+		}
+	})
+
+	for _, method := range []string{http.MethodPost, http.MethodPut} {
+		t.Run(method+"IsNotRetried", func(t *testing.T) {
+			var attempts int
+
+			ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}, Retry(policy))
+			defer ts.Close()
+
+			resp, err := c.do(context.Background(), method, "/foo", nil, bytes.NewReader([]byte("foo-body")))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if got, want := attempts, 1; got != want {
+				t.Errorf("attempts = %d, want %d", got, want)
+			}
+		})
+	}
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}, Retry(RetryPolicy{MaxAttempts: 2, MaxBackoff: time.Minute}))
+		defer ts.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if _, err := c.Platforms(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for _, tt := range []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{5, 500 * time.Millisecond, time.Second},
+		{70, 500 * time.Millisecond, time.Second},
+	} {
+		if got := p.backoff(tt.attempt, nil); got < tt.min || got > tt.max {
+			t.Errorf("p.backoff(%d, nil) = %v, want between %v and %v", tt.attempt, got, tt.min, tt.max)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"3"}}}
+
+	if got, want := p.backoff(1, resp), time.Second; got != want {
+		t.Errorf("p.backoff(1, resp) = %v, want %v", got, want)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, tt := range []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"foo", 0, false},
+		{"-1", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"Thu, 02 Jan 2020 03:04:35 GMT", 30 * time.Second, true},
+		{"Thu, 02 Jan 2020 03:00:00 GMT", 0, true},
+	} {
+		got, ok := retryAfter(tt.value, now)
+
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package restapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AssetQuery holds the search criteria used by SearchAssets. Zero values are
+// ignored.
+type AssetQuery struct {
+	// Text is a free text query.
+	Text string
+
+	// CategoryID limits the search to a category and its sub categories.
+	CategoryID string
+
+	ChannelID string
+
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+
+	BroadcastFrom time.Time
+	BroadcastTo   time.Time
+
+	Published *bool
+	Archived  *bool
+
+	// Sort is the field to sort by, prefixed with - for descending order,
+	// such as -liveBroadcastTime.
+	Sort string
+
+	// PageSize is the number of assets fetched per request.
+	PageSize int
+}
+
+// SearchAssets returns an iterator over the assets matching query. Pages are
+// fetched lazily as the iterator is consumed, and iteration stops after the
+// first error.
+func (c *Client) SearchAssets(ctx context.Context, platform string, query AssetQuery) iter.Seq2[*Asset, error] {
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	return paginate(pageSize, func(start int) (_ []*Asset, _ int, err error) {
+		ctx, op := c.startOperation(ctx, "SearchAssets", platform)
+		defer op.end(&err)
+
+		return c.searchAssets(ctx, platform, query, start, pageSize)
+	})
+}
+
+func (c *Client) searchAssets(ctx context.Context, platform string, query AssetQuery, start, size int) ([]*Asset, int, error) {
+	categoryID := query.CategoryID
+	if categoryID == "" {
+		categoryID = "root"
+	}
+
+	path := fmt.Sprintf("/api/%s/search/categories/%s/assets", platform, categoryID)
+
+	params := query.values()
+	params.Set("start", strconv.Itoa(start))
+	params.Set("size", strconv.Itoa(size))
+
+	resp, err := c.get(ctx, path, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, newAPIError(resp)
+	}
+
+	return parseAssetSearch(resp.Body)
+}
+
+// values returns the query parameters for q. Criteria other than the free
+// text are combined into a Lucene query.
+func (q AssetQuery) values() url.Values {
+	v := url.Values{"expand": {"metadata,category"}}
+
+	if q.Text != "" {
+		v.Set("text", q.Text)
+	}
+
+	if q.Sort != "" {
+		v.Set("sort", q.Sort)
+	}
+
+	var terms []string
+
+	if q.ChannelID != "" {
+		terms = append(terms, "channelId:"+q.ChannelID)
+	}
+
+	if r := luceneRange("createTime", q.CreatedFrom, q.CreatedTo); r != "" {
+		terms = append(terms, r)
+	}
+
+	if r := luceneRange("liveBroadcastTime", q.BroadcastFrom, q.BroadcastTo); r != "" {
+		terms = append(terms, r)
+	}
+
+	if q.Published != nil {
+		terms = append(terms, "itemsPublished:"+strconv.FormatBool(*q.Published))
+	}
+
+	if q.Archived != nil {
+		terms = append(terms, "archive:"+strconv.FormatBool(*q.Archived))
+	}
+
+	if len(terms) > 0 {
+		v.Set("query", strings.Join(terms, " AND "))
+	}
+
+	return v
+}
+
+// luceneRange returns a Lucene range query for field, or an empty string if
+// both from and to are zero.
+func luceneRange(field string, from, to time.Time) string {
+	if from.IsZero() && to.IsZero() {
+		return ""
+	}
+
+	bound := func(t time.Time) string {
+		if t.IsZero() {
+			return "*"
+		}
+
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	return fmt.Sprintf("%s:[%s TO %s]", field, bound(from), bound(to))
+}
+
+func parseAssetSearch(r io.Reader) ([]*Asset, int, error) {
+	var resp struct {
+		Assets struct {
+			Asset        []json.RawMessage `json:"asset"`
+			NumberOfHits *int              `json:"numberOfHits"`
+		} `json:"assets"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, 0, err
+	}
+
+	assets := make([]*Asset, 0, len(resp.Assets.Asset))
+
+	for _, raw := range resp.Assets.Asset {
+		asset, err := parseAsset(bytes.NewReader(raw))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		assets = append(assets, asset)
+	}
+
+	total := -1
+	if resp.Assets.NumberOfHits != nil {
+		total = *resp.Assets.NumberOfHits
+	}
+
+	return assets, total, nil
+}
@@ -0,0 +1,131 @@
+package restapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSearchAssets(t *testing.T) {
+	t.Run("Pages", func(t *testing.T) {
+		var starts []string
+
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			if got, want := r.URL.Path, "/api/foo-platform/search/categories/123/assets"; got != want {
+				t.Errorf("r.URL.Path = %q, want %q", got, want)
+			}
+
+			if got, want := r.URL.Query().Get("text"), "foo"; got != want {
+				t.Errorf("text = %q, want %q", got, want)
+			}
+
+			if got, want := r.URL.Query().Get("size"), "2"; got != want {
+				t.Errorf("size = %q, want %q", got, want)
+			}
+
+			start := r.URL.Query().Get("start")
+			starts = append(starts, start)
+
+			switch start {
+			case "0":
+				fmt.Fprint(w, `{"assets":{"asset":[{"id":1,"title":"foo"},{"id":2,"title":"bar"}],"numberOfHits":3}}`)
+			case "2":
+				fmt.Fprint(w, `{"assets":{"asset":[{"id":3,"title":"baz"}],"numberOfHits":3}}`)
+			default:
+				t.Errorf("unexpected start %q", start)
+			}
+		})
+		defer ts.Close()
+
+		var ids []string
+
+		for asset, err := range c.SearchAssets(context.Background(), "foo-platform", AssetQuery{Text: "foo", CategoryID: "123", PageSize: 2}) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ids = append(ids, asset.ID)
+		}
+
+		if got, want := fmt.Sprint(ids), "[1 2 3]"; got != want {
+			t.Errorf("ids = %s, want %s", got, want)
+		}
+
+		if got, want := fmt.Sprint(starts), "[0 2]"; got != want {
+			t.Errorf("starts = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("Break", func(t *testing.T) {
+		var requests int
+
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			fmt.Fprint(w, `{"assets":{"asset":[{"id":1},{"id":2}]}}`)
+		})
+		defer ts.Close()
+
+		for range c.SearchAssets(context.Background(), "foo-platform", AssetQuery{PageSize: 2}) {
+			break
+		}
+
+		if got, want := requests, 1; got != want {
+			t.Errorf("requests = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		})
+		defer ts.Close()
+
+		var n int
+
+		for asset, err := range c.SearchAssets(context.Background(), "foo-platform", AssetQuery{}) {
+			n++
+
+			if asset != nil {
+				t.Errorf("asset = %+v, want nil", asset)
+			}
+
+			if !errors.Is(err, ErrUnknown) {
+				t.Errorf("err = %v, want %v", err, ErrUnknown)
+			}
+		}
+
+		if got, want := n, 1; got != want {
+			t.Errorf("got %d results, want %d", got, want)
+		}
+	})
+}
+
+func TestAssetQueryValues(t *testing.T) {
+	published := true
+
+	for i, tt := range []struct {
+		query AssetQuery
+		want  string
+	}{
+		{AssetQuery{}, ""},
+		{AssetQuery{ChannelID: "123"}, "channelId:123"},
+		{AssetQuery{Published: &published}, "itemsPublished:true"},
+		{
+			AssetQuery{
+				CreatedFrom: time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC),
+				BroadcastTo: time.Date(2020, time.February, 2, 3, 4, 5, 0, time.UTC),
+			},
+			"createTime:[2020-01-02T03:04:05Z TO *] AND liveBroadcastTime:[* TO 2020-02-02T03:04:05Z]",
+		},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if got := tt.query.values().Get("query"); got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package restapi
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// AssetService is the part of the Vimond REST API dealing with assets and
+// their metadata.
+type AssetService interface {
+	Asset(ctx context.Context, platform, assetID string) (*Asset, error)
+	AssetRaw(ctx context.Context, platform, assetID, headerAccept string) ([]byte, error)
+	CreateAsset(ctx context.Context, platform string, asset *Asset) (*Asset, error)
+	UpdateAsset(ctx context.Context, platform string, asset *Asset) (*Asset, error)
+	UpdateAssetFields(ctx context.Context, platform, assetID string, values map[string]interface{}) (*Asset, error)
+	DeleteAsset(ctx context.Context, platform, assetID string) error
+	SearchAssets(ctx context.Context, platform string, query AssetQuery) iter.Seq2[*Asset, error]
+	SetAssetMetadata(ctx context.Context, platform, assetID string, entries MetadataMap) (MetadataMap, error)
+	UpdateAssetMetadata(ctx context.Context, platform, assetID string, changes ...MetadataChange) (MetadataMap, error)
+}
+
+// OrderService is the part of the Vimond REST API dealing with orders.
+type OrderService interface {
+	Order(ctx context.Context, platform, orderID string) (*Order, error)
+	CurrentOrders(ctx context.Context, platform, userID string) ([]*Order, error)
+	OrderHistory(ctx context.Context, platform, userID string) ([]*Order, error)
+	CreateOrder(ctx context.Context, platform, userID, productPaymentID string) (*Order, error)
+	TerminateOrder(ctx context.Context, platform, orderID string, immediately bool) (*Order, error)
+	CancelAutoRenew(ctx context.Context, platform, orderID string) (*Order, error)
+	ReactivateOrder(ctx context.Context, platform, orderID string) (*Order, error)
+	PatchOrder(ctx context.Context, platform, orderID string, values map[string]interface{}, opts PatchOrderOptions) (*PatchOrderResult, error)
+	SetOrderEndDates(ctx context.Context, platform, orderID string, endDate time.Time) (*Order, error)
+}
+
+// PlatformService is the part of the Vimond REST API dealing with platforms.
+type PlatformService interface {
+	Platforms(ctx context.Context) ([]Platform, error)
+}
+
+// VideofileService is the part of the Vimond REST API dealing with the video
+// files of assets.
+type VideofileService interface {
+	Videofiles(ctx context.Context, assetID string) (*VideofilesResponse, error)
+}
+
+var (
+	_ AssetService     = (*Client)(nil)
+	_ OrderService     = (*Client)(nil)
+	_ PlatformService  = (*Client)(nil)
+	_ VideofileService = (*Client)(nil)
+)
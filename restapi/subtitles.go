@@ -0,0 +1,334 @@
+package restapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Subtitle formats
+const (
+	SubtitleWebVTT = "webvtt"
+	SubtitleSRT    = "srt"
+	SubtitleTTML   = "ttml"
+)
+
+// SubtitleTrack is a subtitle track of an asset.
+type SubtitleTrack struct {
+	ID     string
+	Lang   string
+	Format string
+	URL    string
+
+	// Forced subtitles are shown even if subtitles are turned off, such as
+	// for foreign language dialogue.
+	Forced bool
+
+	// ClosedCaptions is set for tracks that also describe sounds, for the
+	// deaf and hard of hearing.
+	ClosedCaptions bool
+}
+
+// Subtitles returns the subtitle tracks of an asset.
+func (c *Client) Subtitles(ctx context.Context, platform, assetID string) (_ []SubtitleTrack, err error) {
+	ctx, op := c.startOperation(ctx, "Subtitles", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	resp, err := c.get(ctx, c.assetPath(platform, assetID)+"/subtitles", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseSubtitleTracks(resp.Body)
+}
+
+// UploadSubtitle uploads a subtitle file as a new track of an asset, with the
+// language, format and flags of the given track. The file is read into memory
+// and validated with ValidateSubtitle before it is uploaded.
+func (c *Client) UploadSubtitle(ctx context.Context, platform, assetID string, track SubtitleTrack, r io.Reader) (_ *SubtitleTrack, err error) {
+	ctx, op := c.startOperation(ctx, "UploadSubtitle", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return nil, ErrInvalidAssetID
+	}
+
+	if track.Lang == "" {
+		return nil, fmt.Errorf("%w: missing lang", ErrInvalidSubtitle)
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateSubtitle(track.Format, data); err != nil {
+		return nil, err
+	}
+
+	fields := url.Values{
+		"locale":         {track.Lang},
+		"format":         {track.Format},
+		"forced":         {strconv.FormatBool(track.Forced)},
+		"closedCaptions": {strconv.FormatBool(track.ClosedCaptions)},
+	}
+
+	filename := track.Lang + subtitleExtensions[track.Format]
+
+	body, ct, err := multipartBody(fields, filename, subtitleMediaTypes[track.Format], data)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, c.assetPath(platform, assetID)+"/subtitles", url.Values{}, body, contentType(ct))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, newAPIError(resp)
+	}
+
+	return parseSubtitleTrack(resp.Body)
+}
+
+// DeleteSubtitle deletes a subtitle track of an asset.
+func (c *Client) DeleteSubtitle(ctx context.Context, platform, assetID, subtitleID string) (err error) {
+	ctx, op := c.startOperation(ctx, "DeleteSubtitle", platform, AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
+	if _, err := strconv.Atoi(assetID); err != nil {
+		return ErrInvalidAssetID
+	}
+
+	resp, err := c.delete(ctx, c.assetPath(platform, assetID)+"/subtitles/"+url.PathEscape(subtitleID), url.Values{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+var (
+	subtitleExtensions = map[string]string{
+		SubtitleWebVTT: ".vtt",
+		SubtitleSRT:    ".srt",
+		SubtitleTTML:   ".ttml",
+	}
+
+	subtitleMediaTypes = map[string]string{
+		SubtitleWebVTT: "text/vtt",
+		SubtitleSRT:    "application/x-subrip",
+		SubtitleTTML:   "application/ttml+xml",
+	}
+
+	vttTiming = regexp.MustCompile(`^(\d{2,}:)?\d{2}:\d{2}\.\d{3} +--> +(\d{2,}:)?\d{2}:\d{2}\.\d{3}( |$)`)
+	srtTiming = regexp.MustCompile(`^\d{2,}:\d{2}:\d{2},\d{3} +--> +\d{2,}:\d{2}:\d{2},\d{3}( |$)`)
+)
+
+// ValidateSubtitle checks that data is a well-formed subtitle file of the
+// given format. Only the structure and cue timings are checked, not the cue
+// text. The returned error matches ErrInvalidSubtitle.
+func ValidateSubtitle(format string, data []byte) error {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	var err error
+
+	switch format {
+	case SubtitleWebVTT:
+		err = validateWebVTT(data)
+	case SubtitleSRT:
+		err = validateSRT(data)
+	case SubtitleTTML:
+		err = validateTTML(data)
+	default:
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidSubtitle, format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidSubtitle, format, err)
+	}
+
+	return nil
+}
+
+func validateWebVTT(data []byte) error {
+	s := bufio.NewScanner(bytes.NewReader(data))
+
+	if !s.Scan() || !vttHeader(s.Text()) {
+		return fmt.Errorf("missing WEBVTT header")
+	}
+
+	for line := 2; s.Scan(); line++ {
+		if text := s.Text(); strings.Contains(text, "-->") && !vttTiming.MatchString(text) {
+			return fmt.Errorf("line %d: invalid cue timing %q", line, text)
+		}
+	}
+
+	return s.Err()
+}
+
+func vttHeader(line string) bool {
+	rest, ok := strings.CutPrefix(line, "WEBVTT")
+
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+func validateSRT(data []byte) error {
+	s := bufio.NewScanner(bytes.NewReader(data))
+
+	var (
+		cues  int
+		state int // 0: before cue, 1: after index, 2: in cue text
+	)
+
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+
+		switch {
+		case state == 0 && text == "":
+		case state == 0:
+			if _, err := strconv.Atoi(strings.TrimSpace(text)); err != nil {
+				return fmt.Errorf("line %d: invalid cue index %q", line, text)
+			}
+
+			state = 1
+		case state == 1:
+			if !srtTiming.MatchString(text) {
+				return fmt.Errorf("line %d: invalid cue timing %q", line, text)
+			}
+
+			cues++
+			state = 2
+		case text == "":
+			state = 0
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	if state == 1 {
+		return fmt.Errorf("missing timing of last cue")
+	}
+
+	if cues == 0 {
+		return fmt.Errorf("no cues")
+	}
+
+	return nil
+}
+
+func validateTTML(data []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	root := true
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if se, ok := tok.(xml.StartElement); ok && root {
+			if se.Name.Local != "tt" || !strings.HasPrefix(se.Name.Space, "http://www.w3.org/ns/ttml") {
+				return fmt.Errorf("root element is not tt in the TTML namespace")
+			}
+
+			root = false
+		}
+	}
+
+	if root {
+		return fmt.Errorf("missing tt element")
+	}
+
+	return nil
+}
+
+type vimondSubtitleTrack struct {
+	ID             int    `json:"id"`
+	Locale         string `json:"locale"`
+	Format         string `json:"format"`
+	URL            string `json:"url"`
+	Forced         bool   `json:"forced"`
+	ClosedCaptions bool   `json:"closedCaptions"`
+}
+
+func (vst *vimondSubtitleTrack) subtitleTrack() SubtitleTrack {
+	return SubtitleTrack{
+		ID:             strconv.Itoa(vst.ID),
+		Lang:           vst.Locale,
+		Format:         vst.Format,
+		URL:            vst.URL,
+		Forced:         vst.Forced,
+		ClosedCaptions: vst.ClosedCaptions,
+	}
+}
+
+func parseSubtitleTrack(r io.Reader) (*SubtitleTrack, error) {
+	var vst vimondSubtitleTrack
+
+	if err := json.NewDecoder(r).Decode(&vst); err != nil {
+		return nil, err
+	}
+
+	st := vst.subtitleTrack()
+
+	return &st, nil
+}
+
+func parseSubtitleTracks(r io.Reader) ([]SubtitleTrack, error) {
+	var resp []vimondSubtitleTrack
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	tracks := make([]SubtitleTrack, 0, len(resp))
+
+	for n := range resp {
+		tracks = append(tracks, resp[n].subtitleTrack())
+	}
+
+	return tracks, nil
+}
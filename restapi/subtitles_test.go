@@ -0,0 +1,122 @@
+package restapi
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSubtitles(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/api/tv4/asset/123/subtitles"; got != want {
+			t.Errorf("r.URL.Path = %q, want %q", got, want)
+		}
+
+		w.Write([]byte(`[
+			{"id":1,"locale":"sv","format":"webvtt","url":"https://subs/1.vtt","forced":false,"closedCaptions":true},
+			{"id":2,"locale":"en","format":"srt","url":"https://subs/2.srt","forced":true}
+		]`))
+	})
+	defer ts.Close()
+
+	tracks, err := c.Subtitles(context.Background(), "tv4", "123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []SubtitleTrack{
+		{ID: "1", Lang: "sv", Format: SubtitleWebVTT, URL: "https://subs/1.vtt", ClosedCaptions: true},
+		{ID: "2", Lang: "en", Format: SubtitleSRT, URL: "https://subs/2.srt", Forced: true},
+	}
+
+	if !cmp.Equal(tracks, want) {
+		t.Errorf("tracks mismatch (-got +want):\n%s", cmp.Diff(tracks, want))
+	}
+}
+
+func TestUploadSubtitle(t *testing.T) {
+	vtt := "WEBVTT\n\n00:01.000 --> 00:04.000\nHej\n"
+
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Method+" "+r.URL.Path, "POST /api/tv4/asset/123/subtitles"; got != want {
+			t.Errorf("request = %q, want %q", got, want)
+		}
+
+		for name, want := range map[string]string{"locale": "sv", "format": "webvtt", "forced": "false", "closedCaptions": "true"} {
+			if got := r.FormValue(name); got != want {
+				t.Errorf("r.FormValue(%q) = %q, want %q", name, got, want)
+			}
+		}
+
+		f, fh, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		defer f.Close()
+
+		if got, want := fh.Header.Get("Content-Type"), "text/vtt"; got != want {
+			t.Errorf("part Content-Type = %q, want %q", got, want)
+		}
+
+		if b, _ := ioutil.ReadAll(f); string(b) != vtt {
+			t.Errorf("unexpected file content %q", b)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":3,"locale":"sv","format":"webvtt","closedCaptions":true}`))
+	})
+	defer ts.Close()
+
+	track, err := c.UploadSubtitle(context.Background(), "tv4", "123", SubtitleTrack{
+		Lang:           "sv",
+		Format:         SubtitleWebVTT,
+		ClosedCaptions: true,
+	}, strings.NewReader(vtt))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := track.ID, "3"; got != want {
+		t.Errorf("track.ID = %q, want %q", got, want)
+	}
+}
+
+func TestValidateSubtitle(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		format string
+		data   string
+		valid  bool
+	}{
+		{"WebVTT", SubtitleWebVTT, "WEBVTT\n\n00:01.000 --> 00:04.000\nHej\n", true},
+		{"WebVTTHeaderText", SubtitleWebVTT, "\ufeffWEBVTT - Svenska\r\n\r\n01:00:01.000 --> 01:00:04.000 align:start\r\nHej\r\n", true},
+		{"WebVTTMissingHeader", SubtitleWebVTT, "00:01.000 --> 00:04.000\nHej\n", false},
+		{"WebVTTBadTiming", SubtitleWebVTT, "WEBVTT\n\n00:01,000 --> 00:04,000\nHej\n", false},
+		{"SRT", SubtitleSRT, "1\n00:00:01,000 --> 00:00:04,000\nHej\n\n2\n00:00:05,000 --> 00:00:06,000\nHopp\n", true},
+		{"SRTBadIndex", SubtitleSRT, "one\n00:00:01,000 --> 00:00:04,000\nHej\n", false},
+		{"SRTBadTiming", SubtitleSRT, "1\n00:00:01.000 --> 00:00:04.000\nHej\n", false},
+		{"SRTEmpty", SubtitleSRT, "\n\n", false},
+		{"TTML", SubtitleTTML, `<?xml version="1.0"?><tt xmlns="http://www.w3.org/ns/ttml"><body><div><p begin="00:00:01.000" end="00:00:04.000">Hej</p></div></body></tt>`, true},
+		{"TTMLWrongRoot", SubtitleTTML, `<html><body></body></html>`, false},
+		{"TTMLMalformed", SubtitleTTML, `<tt xmlns="http://www.w3.org/ns/ttml"><body>`, false},
+		{"UnknownFormat", "sami", "", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSubtitle(tt.format, []byte(tt.data))
+
+			if tt.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.valid && !errors.Is(err, ErrInvalidSubtitle) {
+				t.Fatalf("err = %v, want ErrInvalidSubtitle", err)
+			}
+		})
+	}
+}
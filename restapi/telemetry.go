@@ -0,0 +1,251 @@
+package restapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/TV4/vimond/restapi"
+
+// Telemetry attribute keys
+const (
+	AttributeMethod     = attribute.Key("vimond.method")
+	AttributePlatform   = attribute.Key("vimond.platform")
+	AttributeAssetID    = attribute.Key("vimond.asset.id")
+	AttributeOrderID    = attribute.Key("vimond.order.id")
+	AttributeRetryCount = attribute.Key("vimond.retry_count")
+)
+
+// Telemetry makes the *client create an OpenTelemetry span for every call of
+// an API method, such as vimond.Asset, with a child span for every HTTP
+// request it sends, including retries. Iterators get a span per page.
+//
+// API method spans carry the platform, any asset or order ID, the HTTP status
+// code of the last response and the number of retries. Call durations are
+// recorded in the vimond.client.duration histogram, and failed calls are
+// counted by vimond.client.errors, both by method, platform, status code and
+// error type. No request headers are recorded, so the api key and signature
+// never are.
+//
+// The global providers are used for nil providers.
+func Telemetry(tp trace.TracerProvider, mp metric.MeterProvider) func(*Client) {
+	return func(c *Client) {
+		if tp == nil {
+			tp = otel.GetTracerProvider()
+		}
+
+		if mp == nil {
+			mp = otel.GetMeterProvider()
+		}
+
+		meter := mp.Meter(instrumentationName)
+
+		t := &telemetry{tracer: tp.Tracer(instrumentationName)}
+
+		t.duration, _ = meter.Float64Histogram("vimond.client.duration",
+			metric.WithDescription("Duration of Vimond REST API calls, including retries."),
+			metric.WithUnit("s"))
+
+		t.errors, _ = meter.Int64Counter("vimond.client.errors",
+			metric.WithDescription("Number of failed Vimond REST API calls."),
+			metric.WithUnit("{call}"))
+
+		c.telemetry = t
+	}
+}
+
+type telemetry struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+// operation is a call of an API method, spanning all of its requests,
+// including those of the API methods it calls.
+type operation struct {
+	telemetry *telemetry
+	parent    *operation
+	ctx       context.Context
+	span      trace.Span
+	start     time.Time
+	method    string
+	platform  string
+
+	mu      sync.Mutex
+	status  int
+	retries int
+}
+
+type operationKey struct{}
+
+// startOperation starts an operation for a call of the named API method, to
+// be ended by deferring op.end(&err). It is a no-op, returning a nil
+// *operation, if telemetry is not enabled.
+func (c *Client) startOperation(ctx context.Context, method, platform string, attrs ...attribute.KeyValue) (context.Context, *operation) {
+	t := c.telemetry
+
+	if t == nil {
+		return ctx, nil
+	}
+
+	attrs = append([]attribute.KeyValue{AttributeMethod.String(method)}, attrs...)
+
+	if platform != "" {
+		attrs = append(attrs, AttributePlatform.String(platform))
+	}
+
+	ctx, span := t.tracer.Start(ctx, "vimond."+method, trace.WithAttributes(attrs...))
+
+	op := &operation{
+		telemetry: t,
+		parent:    operationFrom(ctx),
+		span:      span,
+		start:     time.Now(),
+		method:    method,
+		platform:  platform,
+	}
+
+	op.ctx = context.WithValue(ctx, operationKey{}, op)
+
+	return op.ctx, op
+}
+
+// end ends the operation with the error returned by the API method.
+func (op *operation) end(errp *error) {
+	if op == nil {
+		return
+	}
+
+	op.mu.Lock()
+	status, retries := op.status, op.retries
+	op.mu.Unlock()
+
+	var attrs []attribute.KeyValue
+
+	if status != 0 {
+		attrs = append(attrs, attribute.Int("http.response.status_code", status))
+	}
+
+	if err := *errp; err != nil {
+		errorType := fmt.Sprintf("%T", err)
+
+		var apiErr *APIError
+
+		if errors.As(err, &apiErr) {
+			errorType = strconv.Itoa(apiErr.StatusCode)
+		}
+
+		attrs = append(attrs, attribute.String("error.type", errorType))
+
+		op.span.RecordError(err)
+		op.span.SetStatus(codes.Error, err.Error())
+	}
+
+	op.span.SetAttributes(append(attrs, AttributeRetryCount.Int(retries))...)
+	op.span.End()
+
+	// Only bounded values are used as metric attributes, leaving out IDs.
+	attrs = append(attrs, AttributeMethod.String(op.method))
+
+	if op.platform != "" {
+		attrs = append(attrs, AttributePlatform.String(op.platform))
+	}
+
+	set := metric.WithAttributes(attrs...)
+
+	op.telemetry.duration.Record(op.ctx, time.Since(op.start).Seconds(), set)
+
+	if *errp != nil {
+		op.telemetry.errors.Add(op.ctx, 1, set)
+	}
+}
+
+// observe records the status code of a response, and whether it was the
+// response to a retry, in the operation and the operations calling it.
+func (op *operation) observe(status int, retry bool) {
+	for ; op != nil; op = op.parent {
+		op.mu.Lock()
+
+		if status != 0 {
+			op.status = status
+		}
+
+		if retry {
+			op.retries++
+		}
+
+		op.mu.Unlock()
+	}
+}
+
+func operationFrom(ctx context.Context) *operation {
+	op, _ := ctx.Value(operationKey{}).(*operation)
+
+	return op
+}
+
+// attemptSpan is the span of a single HTTP request.
+type attemptSpan struct {
+	span trace.Span
+	op   *operation
+}
+
+// startAttempt starts the span of the nth attempt of an HTTP request, as a
+// child of the operation in ctx. It is a no-op, returning a nil
+// *attemptSpan, if telemetry is not enabled.
+func (t *telemetry) startAttempt(ctx context.Context, method string, n int) (context.Context, *attemptSpan) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	op := operationFrom(ctx)
+
+	attrs := []attribute.KeyValue{attribute.String("http.request.method", method)}
+
+	if n > 1 {
+		attrs = append(attrs, attribute.Int("http.request.resend_count", n-1))
+
+		op.observe(0, true)
+	}
+
+	ctx, span := t.tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	return ctx, &attemptSpan{span: span, op: op}
+}
+
+// end ends the span with the response or error of the attempt.
+func (a *attemptSpan) end(resp *http.Response, err error) {
+	if a == nil {
+		return
+	}
+
+	switch {
+	case err != nil:
+		a.span.RecordError(err)
+		a.span.SetStatus(codes.Error, err.Error())
+	case resp.StatusCode >= 400:
+		a.span.SetStatus(codes.Error, resp.Status)
+	}
+
+	if resp != nil {
+		a.span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+		a.op.observe(resp.StatusCode, false)
+	}
+
+	a.span.End()
+}
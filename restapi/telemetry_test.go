@@ -0,0 +1,281 @@
+package restapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTelemetry(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	attempt := 0
+
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tv4/asset/123":
+			if attempt++; attempt == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			w.Write([]byte(`{"id":123}`))
+		case "/api/tv4/order/789":
+			if r.Method == http.MethodPut {
+				w.Write([]byte(`{"id":789,"endDate":"1970-01-01T00:00:02Z"}`))
+				return
+			}
+
+			w.Write([]byte(`{"id":789,"endDate":1000}`))
+		default:
+			http.NotFound(w, r)
+		}
+	},
+		Credentials("key123", "secret456"),
+		Retry(RetryPolicy{MaxAttempts: 2, MaxBackoff: time.Millisecond}),
+		Telemetry(
+			sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+			sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		),
+	)
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	if _, err := c.Asset(ctx, "tv4", "123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := c.Order(ctx, "tv4", "456"); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	if _, err := c.SetOrderEndDates(ctx, "tv4", "789", time.Unix(2, 0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ended := spans.Ended()
+
+	named := func(name string) []sdktrace.ReadOnlySpan {
+		var found []sdktrace.ReadOnlySpan
+
+		for _, span := range ended {
+			if span.Name() == name {
+				found = append(found, span)
+			}
+		}
+
+		return found
+	}
+
+	children := func(parent sdktrace.ReadOnlySpan) []sdktrace.ReadOnlySpan {
+		var found []sdktrace.ReadOnlySpan
+
+		for _, span := range ended {
+			if span.Parent().SpanID() == parent.SpanContext().SpanID() {
+				found = append(found, span)
+			}
+		}
+
+		return found
+	}
+
+	for _, span := range ended {
+		for _, kv := range span.Attributes() {
+			if v := kv.Value.Emit(); strings.Contains(v, "key123") || strings.Contains(v, "SUMO") {
+				t.Errorf("%s: attribute %s leaks credentials: %q", span.Name(), kv.Key, v)
+			}
+		}
+	}
+
+	for _, want := range []struct {
+		name   string
+		status codes.Code
+		attrs  []attribute.KeyValue
+	}{
+		{
+			name:   "vimond.Asset",
+			status: codes.Unset,
+			attrs: []attribute.KeyValue{
+				AttributeMethod.String("Asset"),
+				AttributeAssetID.String("123"),
+				AttributePlatform.String("tv4"),
+				attribute.Int("http.response.status_code", 200),
+				AttributeRetryCount.Int(1),
+			},
+		},
+		{
+			name:   "vimond.Order",
+			status: codes.Error,
+			attrs: []attribute.KeyValue{
+				AttributeMethod.String("Order"),
+				AttributeOrderID.String("456"),
+				AttributePlatform.String("tv4"),
+				attribute.Int("http.response.status_code", 404),
+				attribute.String("error.type", "404"),
+				AttributeRetryCount.Int(0),
+			},
+		},
+	} {
+		found := named(want.name)
+
+		if got := len(found); got != 1 {
+			t.Fatalf("%d %s spans, want 1", got, want.name)
+		}
+
+		span := found[0]
+
+		if got := span.Status().Code; got != want.status {
+			t.Errorf("%s: status = %v, want %v", want.name, got, want.status)
+		}
+
+		if got := span.Attributes(); !cmp.Equal(got, want.attrs, cmp.Comparer(attributeEqual)) {
+			t.Errorf("%s: attributes mismatch:\ngot  %v\nwant %v", want.name, got, want.attrs)
+		}
+	}
+
+	t.Run("Attempts", func(t *testing.T) {
+		attempts := children(named("vimond.Asset")[0])
+
+		if got, want := len(attempts), 2; got != want {
+			t.Fatalf("len(attempts) = %d, want %d", got, want)
+		}
+
+		for n, want := range []struct {
+			status codes.Code
+			attrs  []attribute.KeyValue
+		}{
+			{
+				status: codes.Error,
+				attrs: []attribute.KeyValue{
+					attribute.String("http.request.method", "GET"),
+					attribute.Int("http.response.status_code", 503),
+				},
+			},
+			{
+				status: codes.Unset,
+				attrs: []attribute.KeyValue{
+					attribute.String("http.request.method", "GET"),
+					attribute.Int("http.request.resend_count", 1),
+					attribute.Int("http.response.status_code", 200),
+				},
+			},
+		} {
+			span := attempts[n]
+
+			if got := span.Name(); got != "GET" {
+				t.Errorf("attempts[%d].Name() = %q, want %q", n, got, "GET")
+			}
+
+			if got := span.Status().Code; got != want.status {
+				t.Errorf("attempts[%d]: status = %v, want %v", n, got, want.status)
+			}
+
+			if got := span.Attributes(); !cmp.Equal(got, want.attrs, cmp.Comparer(attributeEqual)) {
+				t.Errorf("attempts[%d]: attributes mismatch:\ngot  %v\nwant %v", n, got, want.attrs)
+			}
+		}
+	})
+
+	t.Run("NestedMethods", func(t *testing.T) {
+		found := named("vimond.SetOrderEndDates")
+
+		if got := len(found); got != 1 {
+			t.Fatalf("%d vimond.SetOrderEndDates spans, want 1", got)
+		}
+
+		var names []string
+
+		for _, span := range children(found[0]) {
+			names = append(names, span.Name())
+		}
+
+		if want := []string{"vimond.PatchOrder"}; !cmp.Equal(names, want) {
+			t.Errorf("children = %v, want %v", names, want)
+		}
+
+		if got, want := found[0].Attributes(), attribute.Int("http.response.status_code", 200); !containsAttribute(got, want) {
+			t.Errorf("attributes %v missing %v", got, want)
+		}
+
+		patch := named("vimond.PatchOrder")
+
+		if got := len(patch); got != 1 {
+			t.Fatalf("%d vimond.PatchOrder spans, want 1", got)
+		}
+
+		var methods []string
+
+		for _, span := range children(patch[0]) {
+			methods = append(methods, span.Name())
+		}
+
+		if want := []string{"GET", "PUT", "GET"}; !cmp.Equal(methods, want) {
+			t.Errorf("PatchOrder attempts = %v, want %v", methods, want)
+		}
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		var rm metricdata.ResourceMetrics
+
+		if err := reader.Collect(ctx, &rm); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		counts := map[string]int{}
+
+		check := func(name string, set attribute.Set) {
+			for _, key := range []attribute.Key{AttributeAssetID, AttributeOrderID} {
+				if v, ok := set.Value(key); ok {
+					t.Errorf("%s: data point has %s = %q", name, key, v.Emit())
+				}
+			}
+		}
+
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				switch data := m.Data.(type) {
+				case metricdata.Histogram[float64]:
+					for _, dp := range data.DataPoints {
+						counts[m.Name] += int(dp.Count)
+						check(m.Name, dp.Attributes)
+					}
+				case metricdata.Sum[int64]:
+					for _, dp := range data.DataPoints {
+						counts[m.Name] += int(dp.Value)
+						check(m.Name, dp.Attributes)
+					}
+				}
+			}
+		}
+
+		if want := map[string]int{"vimond.client.duration": 4, "vimond.client.errors": 1}; !cmp.Equal(counts, want) {
+			t.Errorf("metric counts = %v, want %v", counts, want)
+		}
+	})
+}
+
+func attributeEqual(a, b attribute.KeyValue) bool {
+	return a.Key == b.Key && a.Value.Emit() == b.Value.Emit()
+}
+
+func containsAttribute(attrs []attribute.KeyValue, kv attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if attributeEqual(a, kv) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,318 @@
+package restapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"iter"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// User holds a subset of the fields of a Vimond user.
+type User struct {
+	ID           string
+	Username     string
+	Email        string
+	FirstName    string
+	LastName     string
+	DateOfBirth  time.Time
+	Gender       string
+	Country      string
+	ZipCode      string
+	MobileNumber string
+	Properties   map[string]string
+	Registered   time.Time
+	LastLogin    time.Time
+}
+
+// UserUpdate holds the profile fields and properties changed by UpdateUser.
+// Nil fields and properties not in the map are left unchanged.
+type UserUpdate struct {
+	Email        *string
+	FirstName    *string
+	LastName     *string
+	DateOfBirth  *time.Time
+	Gender       *string
+	Country      *string
+	ZipCode      *string
+	MobileNumber *string
+	Properties   map[string]string
+}
+
+// User returns a user.
+func (c *Client) User(ctx context.Context, platform, userID string) (_ *User, err error) {
+	ctx, op := c.startOperation(ctx, "User", platform)
+	defer op.end(&err)
+
+	return c.getUser(ctx, c.userPath(platform, userID), url.Values{})
+}
+
+// UserByEmail returns the user with the given email address.
+func (c *Client) UserByEmail(ctx context.Context, platform, email string) (_ *User, err error) {
+	ctx, op := c.startOperation(ctx, "UserByEmail", platform)
+	defer op.end(&err)
+
+	return c.getUser(ctx, fmt.Sprintf("/api/%s/user", platform), url.Values{"email": {email}})
+}
+
+// UserByUsername returns the user with the given username.
+func (c *Client) UserByUsername(ctx context.Context, platform, username string) (_ *User, err error) {
+	ctx, op := c.startOperation(ctx, "UserByUsername", platform)
+	defer op.end(&err)
+
+	return c.getUser(ctx, fmt.Sprintf("/api/%s/user", platform), url.Values{"username": {username}})
+}
+
+// SearchUsers returns an iterator over the users matching the free text
+// query, such as a part of a name or an email address. Pages of pageSize
+// users are fetched lazily as the iterator is consumed, and iteration stops
+// after the first error.
+func (c *Client) SearchUsers(ctx context.Context, platform, query string, pageSize int) iter.Seq2[*User, error] {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	return paginate(pageSize, func(start int) (_ []*User, _ int, err error) {
+		ctx, op := c.startOperation(ctx, "SearchUsers", platform)
+		defer op.end(&err)
+
+		params := url.Values{
+			"query": {query},
+			"start": {strconv.Itoa(start)},
+			"size":  {strconv.Itoa(pageSize)},
+		}
+
+		resp, err := c.get(ctx, fmt.Sprintf("/api/%s/user/search", platform), params)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer func() {
+			io.CopyN(ioutil.Discard, resp.Body, 64)
+			resp.Body.Close()
+		}()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, 0, newAPIError(resp)
+		}
+
+		return parseUserSearch(resp.Body)
+	})
+}
+
+// UpdateUser updates the profile fields and properties of a user. This
+// method fetches the given user, strips null values, applies the update, and
+// PUTs the resulting object back, so that fields and properties not in the
+// update are left unchanged.
+func (c *Client) UpdateUser(ctx context.Context, platform, userID string, update UserUpdate) (_ *User, err error) {
+	ctx, op := c.startOperation(ctx, "UpdateUser", platform)
+	defer op.end(&err)
+
+	resp, err := c.get(ctx, c.userPath(platform, userID), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var rawUser map[string]interface{}
+
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+
+	if err := dec.Decode(&rawUser); err != nil {
+		return nil, err
+	}
+
+	for k, v := range rawUser {
+		if v == nil {
+			delete(rawUser, k)
+		}
+	}
+
+	update.apply(rawUser)
+
+	body, err := json.Marshal(rawUser)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err = c.put(ctx, c.userPath(platform, userID), url.Values{}, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseUser(resp.Body)
+}
+
+func (c *Client) getUser(ctx context.Context, path string, query url.Values) (*User, error) {
+	resp, err := c.get(ctx, path, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return parseUser(resp.Body)
+}
+
+func (c *Client) userPath(platform, userID string) string {
+	return fmt.Sprintf("/api/%s/user/%s", platform, userID)
+}
+
+type vimondUser struct {
+	ID           int       `json:"id"`
+	Username     string    `json:"userName"`
+	Email        string    `json:"email"`
+	FirstName    string    `json:"firstName"`
+	LastName     string    `json:"lastName"`
+	DateOfBirth  time.Time `json:"dateOfBirth"`
+	Gender       string    `json:"gender"`
+	Country      string    `json:"country"`
+	ZipCode      string    `json:"zip"`
+	MobileNumber string    `json:"mobileNumber"`
+	Registered   time.Time `json:"registrationDate"`
+	LastLogin    time.Time `json:"lastLoginDate"`
+	Properties   []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"properties"`
+}
+
+func (vu *vimondUser) user() *User {
+	u := &User{
+		ID:           strconv.Itoa(vu.ID),
+		Username:     vu.Username,
+		Email:        vu.Email,
+		FirstName:    vu.FirstName,
+		LastName:     vu.LastName,
+		DateOfBirth:  vu.DateOfBirth,
+		Gender:       vu.Gender,
+		Country:      vu.Country,
+		ZipCode:      vu.ZipCode,
+		MobileNumber: vu.MobileNumber,
+		Registered:   vu.Registered,
+		LastLogin:    vu.LastLogin,
+	}
+
+	if len(vu.Properties) > 0 {
+		u.Properties = make(map[string]string, len(vu.Properties))
+
+		for _, p := range vu.Properties {
+			u.Properties[p.Name] = p.Value
+		}
+	}
+
+	return u
+}
+
+// apply sets the fields and properties of the update on rawUser, a user as
+// returned by Vimond. Properties are updated in place, and new properties
+// are appended in name order.
+func (uu UserUpdate) apply(rawUser map[string]interface{}) {
+	for k, v := range map[string]interface{}{
+		"email":        uu.Email,
+		"firstName":    uu.FirstName,
+		"lastName":     uu.LastName,
+		"gender":       uu.Gender,
+		"country":      uu.Country,
+		"zip":          uu.ZipCode,
+		"mobileNumber": uu.MobileNumber,
+	} {
+		if s := v.(*string); s != nil {
+			rawUser[k] = *s
+		}
+	}
+
+	if uu.DateOfBirth != nil {
+		rawUser["dateOfBirth"] = *uu.DateOfBirth
+	}
+
+	if len(uu.Properties) == 0 {
+		return
+	}
+
+	properties, _ := rawUser["properties"].([]interface{})
+
+	updated := map[string]bool{}
+
+	for _, p := range properties {
+		if p, ok := p.(map[string]interface{}); ok {
+			name, _ := p["name"].(string)
+
+			if value, ok := uu.Properties[name]; ok {
+				p["value"] = value
+				updated[name] = true
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(uu.Properties)) {
+		if !updated[name] {
+			properties = append(properties, map[string]interface{}{"name": name, "value": uu.Properties[name]})
+		}
+	}
+
+	rawUser["properties"] = properties
+}
+
+func parseUser(r io.Reader) (*User, error) {
+	var vu vimondUser
+
+	if err := json.NewDecoder(r).Decode(&vu); err != nil {
+		return nil, err
+	}
+
+	return vu.user(), nil
+}
+
+func parseUserSearch(r io.Reader) ([]*User, int, error) {
+	var resp struct {
+		Users        []vimondUser `json:"users"`
+		NumberOfHits *int         `json:"numberOfHits"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, 0, err
+	}
+
+	users := make([]*User, 0, len(resp.Users))
+
+	for n := range resp.Users {
+		users = append(users, resp.Users[n].user())
+	}
+
+	total := -1
+	if resp.NumberOfHits != nil {
+		total = *resp.NumberOfHits
+	}
+
+	return users, total, nil
+}
@@ -0,0 +1,139 @@
+package restapi
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUser(t *testing.T) {
+	const userJSON = `{
+		"id": 345678,
+		"userName": "foo-username",
+		"email": "foo@example.com",
+		"firstName": "Foo",
+		"lastName": "Bar",
+		"dateOfBirth": "2000-01-02T00:00:00Z",
+		"properties": [{"name": "foo-property", "value": "bar"}]
+	}`
+
+	for _, tt := range []struct {
+		name  string
+		fetch func(c *Client) (*User, error)
+		path  string
+		query string
+	}{
+		{"ByID", func(c *Client) (*User, error) { return c.User(context.Background(), "tv4", "345678") }, "/api/tv4/user/345678", ""},
+		{"ByEmail", func(c *Client) (*User, error) { return c.UserByEmail(context.Background(), "tv4", "foo@example.com") }, "/api/tv4/user", "email=foo%40example.com"},
+		{"ByUsername", func(c *Client) (*User, error) { return c.UserByUsername(context.Background(), "tv4", "foo-username") }, "/api/tv4/user", "username=foo-username"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.URL.Path, tt.path; got != want {
+					t.Errorf("r.URL.Path = %q, want %q", got, want)
+				}
+
+				if got, want := r.URL.RawQuery, tt.query; got != want {
+					t.Errorf("r.URL.RawQuery = %q, want %q", got, want)
+				}
+
+				w.Write([]byte(userJSON))
+			})
+			defer ts.Close()
+
+			user, err := tt.fetch(c)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := user.ID, "345678"; got != want {
+				t.Errorf("user.ID = %q, want %q", got, want)
+			}
+
+			if got, want := user.Username, "foo-username"; got != want {
+				t.Errorf("user.Username = %q, want %q", got, want)
+			}
+
+			if got, want := user.Email, "foo@example.com"; got != want {
+				t.Errorf("user.Email = %q, want %q", got, want)
+			}
+
+			if got, want := user.DateOfBirth, time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+				t.Errorf("user.DateOfBirth = %v, want %v", got, want)
+			}
+
+			if got, want := user.Properties["foo-property"], "bar"; got != want {
+				t.Errorf(`user.Properties["foo-property"] = %q, want %q`, got, want)
+			}
+		})
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query().Get("query"), "foo"; got != want {
+			t.Errorf("query = %q, want %q", got, want)
+		}
+
+		switch r.URL.Query().Get("start") {
+		case "0":
+			w.Write([]byte(`{"users":[{"id":1},{"id":2}],"numberOfHits":3}`))
+		case "2":
+			w.Write([]byte(`{"users":[{"id":3}],"numberOfHits":3}`))
+		default:
+			t.Errorf("unexpected start %q", r.URL.Query().Get("start"))
+		}
+	})
+	defer ts.Close()
+
+	var ids []string
+
+	for user, err := range c.SearchUsers(context.Background(), "tv4", "foo", 2) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ids = append(ids, user.ID)
+	}
+
+	if got, want := strings.Join(ids, ","), "1,2,3"; got != want {
+		t.Errorf("ids = %q, want %q", got, want)
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"id":345678,"firstName":"Bar","lastName":"Baz","zip":null,"properties":[{"id":1,"name":"b","value":"0"},{"id":2,"name":"c","value":"3"}]}`))
+		case http.MethodPut:
+			b, _ := ioutil.ReadAll(r.Body)
+
+			if got, want := string(b), `{"firstName":"Foo","id":345678,"lastName":"Baz","properties":[{"id":1,"name":"b","value":"2"},{"id":2,"name":"c","value":"3"},{"name":"a","value":"1"}]}`; got != want {
+				t.Errorf("body = %s, want %s", got, want)
+			}
+
+			w.Write([]byte(`{"id":345678,"firstName":"Foo"}`))
+		default:
+			t.Errorf("unexpected method %q", r.Method)
+		}
+	})
+	defer ts.Close()
+
+	firstName := "Foo"
+
+	user, err := c.UpdateUser(context.Background(), "tv4", "345678", UserUpdate{
+		FirstName:  &firstName,
+		Properties: map[string]string{"b": "2", "a": "1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := user.FirstName, "Foo"; got != want {
+		t.Errorf("user.FirstName = %q, want %q", got, want)
+	}
+}
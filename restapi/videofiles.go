@@ -8,10 +8,15 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 )
 
 // Videofiles returns a list of videofiles for the given assetID
-func (c *Client) Videofiles(ctx context.Context, assetID string) (*VideofilesResponse, error) {
+func (c *Client) Videofiles(ctx context.Context, assetID string) (_ *VideofilesResponse, err error) {
+	ctx, op := c.startOperation(ctx, "Videofiles", "", AttributeAssetID.String(assetID))
+	defer op.end(&err)
+
 	resp, err := c.get(ctx, c.videofilesPath(assetID), url.Values{})
 	if err != nil {
 		return nil, err
@@ -21,13 +26,8 @@ func (c *Client) Videofiles(ctx context.Context, assetID string) (*VideofilesRes
 		_ = resp.Body.Close()
 	}()
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		break
-	case http.StatusNotFound:
-		return nil, ErrNotFound
-	default:
-		return nil, ErrUnknown
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
 	}
 
 	var vr VideofilesResponse
@@ -60,3 +60,111 @@ type Videofile struct {
 	URL         string `json:"url"`
 	FileSize    int64  `json:"filesize"`
 }
+
+// PlayURL returns the full URL of the videofile, assembled from its scheme,
+// server, base and URL. The URL is returned as is if it is already absolute.
+func (vf Videofile) PlayURL() string {
+	if strings.Contains(vf.URL, "://") {
+		return vf.URL
+	}
+
+	var parts []string
+
+	for _, p := range []string{vf.Server, vf.Base, vf.URL} {
+		if p = strings.Trim(p, "/"); p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	u := strings.Join(parts, "/")
+
+	if vf.Scheme != "" {
+		u = strings.TrimSuffix(vf.Scheme, "://") + "://" + u
+	}
+
+	return u
+}
+
+// Filter returns a copy of the response with only the videofiles of the given
+// media format.
+func (vr *VideofilesResponse) Filter(mediaFormat string) *VideofilesResponse {
+	filtered := &VideofilesResponse{
+		AssetID: vr.AssetID,
+		Title:   vr.Title,
+	}
+
+	for _, vf := range vr.Videofiles {
+		if strings.EqualFold(vf.MediaFormat, mediaFormat) {
+			filtered.Videofiles = append(filtered.Videofiles, vf)
+		}
+	}
+
+	return filtered
+}
+
+// SortedByBitrate returns the videofiles sorted by ascending bitrate.
+func (vr *VideofilesResponse) SortedByBitrate() []Videofile {
+	sorted := slices.Clone(vr.Videofiles)
+
+	slices.SortStableFunc(sorted, func(a, b Videofile) int {
+		return a.Bitrate - b.Bitrate
+	})
+
+	return sorted
+}
+
+// Highest returns the videofile with the highest bitrate. The second return
+// value is false if there are no videofiles.
+func (vr *VideofilesResponse) Highest() (Videofile, bool) {
+	sorted := vr.SortedByBitrate()
+	if len(sorted) == 0 {
+		return Videofile{}, false
+	}
+
+	return sorted[len(sorted)-1], true
+}
+
+// Lowest returns the videofile with the lowest bitrate. The second return
+// value is false if there are no videofiles.
+func (vr *VideofilesResponse) Lowest() (Videofile, bool) {
+	sorted := vr.SortedByBitrate()
+	if len(sorted) == 0 {
+		return Videofile{}, false
+	}
+
+	return sorted[0], true
+}
+
+// BitrateGap is a gap between two adjacent renditions in a bitrate ladder.
+type BitrateGap struct {
+	From  int
+	To    int
+	Ratio float64
+}
+
+// LadderGaps returns the gaps in the bitrate ladder where the bitrate of a
+// rendition is more than maxRatio times the bitrate of the rendition below
+// it. Renditions with the same bitrate, such as the same rendition in
+// different media formats, count as one.
+func (vr *VideofilesResponse) LadderGaps(maxRatio float64) []BitrateGap {
+	var (
+		gaps []BitrateGap
+		prev int
+	)
+
+	for _, vf := range vr.SortedByBitrate() {
+		if vf.Bitrate <= 0 || vf.Bitrate == prev {
+			continue
+		}
+
+		if prev > 0 {
+			if ratio := float64(vf.Bitrate) / float64(prev); ratio > maxRatio {
+				gaps = append(gaps, BitrateGap{From: prev, To: vf.Bitrate, Ratio: ratio})
+			}
+		}
+
+		prev = vf.Bitrate
+	}
+
+	return gaps
+}
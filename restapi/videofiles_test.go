@@ -18,3 +18,86 @@ func TestVideofilesPath(t *testing.T) {
 		}
 	}
 }
+
+func TestVideofilePlayURL(t *testing.T) {
+	for _, tt := range []struct {
+		vf   Videofile
+		want string
+	}{
+		{Videofile{}, ""},
+		{Videofile{Scheme: "https", Server: "cdn.example.com", Base: "vod/123", URL: "foo.mp4"}, "https://cdn.example.com/vod/123/foo.mp4"},
+		{Videofile{Scheme: "https://", Server: "cdn.example.com/", Base: "/vod/123/", URL: "/foo.mp4"}, "https://cdn.example.com/vod/123/foo.mp4"},
+		{Videofile{Scheme: "rtmp", Server: "cdn.example.com", URL: "foo.mp4"}, "rtmp://cdn.example.com/foo.mp4"},
+		{Videofile{Scheme: "https", Server: "cdn.example.com", URL: "https://other.example.com/foo.mp4"}, "https://other.example.com/foo.mp4"},
+	} {
+		if got := tt.vf.PlayURL(); got != tt.want {
+			t.Errorf("%+v.PlayURL() = %q, want %q", tt.vf, got, tt.want)
+		}
+	}
+}
+
+func TestVideofilesResponseLadder(t *testing.T) {
+	vr := &VideofilesResponse{
+		AssetID: 123,
+		Videofiles: []Videofile{
+			{Bitrate: 3000, MediaFormat: "mp4", URL: "c"},
+			{Bitrate: 500, MediaFormat: "mp4", URL: "a"},
+			{Bitrate: 800, MediaFormat: "mp4", URL: "b"},
+			{Bitrate: 800, MediaFormat: "hls", URL: "d"},
+		},
+	}
+
+	t.Run("Filter", func(t *testing.T) {
+		filtered := vr.Filter("MP4")
+
+		if got, want := len(filtered.Videofiles), 3; got != want {
+			t.Fatalf("got %d videofiles, want %d", got, want)
+		}
+
+		if got, want := filtered.AssetID, 123; got != want {
+			t.Errorf("filtered.AssetID = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("SortedByBitrate", func(t *testing.T) {
+		var urls string
+
+		for _, vf := range vr.SortedByBitrate() {
+			urls += vf.URL
+		}
+
+		if got, want := urls, "abdc"; got != want {
+			t.Errorf("urls = %q, want %q", got, want)
+		}
+
+		if got, want := vr.Videofiles[0].URL, "c"; got != want {
+			t.Errorf("vr.Videofiles[0].URL = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("HighestLowest", func(t *testing.T) {
+		if vf, ok := vr.Highest(); !ok || vf.URL != "c" {
+			t.Errorf("vr.Highest() = %+v, %v, want c", vf, ok)
+		}
+
+		if vf, ok := vr.Lowest(); !ok || vf.URL != "a" {
+			t.Errorf("vr.Lowest() = %+v, %v, want a", vf, ok)
+		}
+
+		if _, ok := (&VideofilesResponse{}).Highest(); ok {
+			t.Errorf("Highest() of empty response is ok")
+		}
+	})
+
+	t.Run("LadderGaps", func(t *testing.T) {
+		gaps := vr.LadderGaps(2)
+
+		if got, want := len(gaps), 1; got != want {
+			t.Fatalf("got %d gaps, want %d", got, want)
+		}
+
+		if got, want := gaps[0], (BitrateGap{From: 800, To: 3000, Ratio: 3.75}); got != want {
+			t.Errorf("gaps[0] = %+v, want %+v", got, want)
+		}
+	})
+}
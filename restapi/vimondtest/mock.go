@@ -0,0 +1,300 @@
+package vimondtest
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/TV4/vimond/restapi"
+)
+
+// ErrNotMocked is returned by the methods of Mock that have no function set.
+var ErrNotMocked = errors.New("vimondtest: method not mocked")
+
+// Mock implements the restapi service interfaces by calling the function
+// fields of the same names, and records all calls. Methods without a
+// function return ErrNotMocked.
+type Mock struct {
+	// AssetService
+	AssetFunc               func(ctx context.Context, platform, assetID string) (*restapi.Asset, error)
+	AssetRawFunc            func(ctx context.Context, platform, assetID, headerAccept string) ([]byte, error)
+	CreateAssetFunc         func(ctx context.Context, platform string, asset *restapi.Asset) (*restapi.Asset, error)
+	UpdateAssetFunc         func(ctx context.Context, platform string, asset *restapi.Asset) (*restapi.Asset, error)
+	UpdateAssetFieldsFunc   func(ctx context.Context, platform, assetID string, values map[string]interface{}) (*restapi.Asset, error)
+	DeleteAssetFunc         func(ctx context.Context, platform, assetID string) error
+	SearchAssetsFunc        func(ctx context.Context, platform string, query restapi.AssetQuery) iter.Seq2[*restapi.Asset, error]
+	SetAssetMetadataFunc    func(ctx context.Context, platform, assetID string, entries restapi.MetadataMap) (restapi.MetadataMap, error)
+	UpdateAssetMetadataFunc func(ctx context.Context, platform, assetID string, changes ...restapi.MetadataChange) (restapi.MetadataMap, error)
+
+	// OrderService
+	OrderFunc            func(ctx context.Context, platform, orderID string) (*restapi.Order, error)
+	CurrentOrdersFunc    func(ctx context.Context, platform, userID string) ([]*restapi.Order, error)
+	OrderHistoryFunc     func(ctx context.Context, platform, userID string) ([]*restapi.Order, error)
+	CreateOrderFunc      func(ctx context.Context, platform, userID, productPaymentID string) (*restapi.Order, error)
+	TerminateOrderFunc   func(ctx context.Context, platform, orderID string, immediately bool) (*restapi.Order, error)
+	CancelAutoRenewFunc  func(ctx context.Context, platform, orderID string) (*restapi.Order, error)
+	ReactivateOrderFunc  func(ctx context.Context, platform, orderID string) (*restapi.Order, error)
+	PatchOrderFunc       func(ctx context.Context, platform, orderID string, values map[string]interface{}, opts restapi.PatchOrderOptions) (*restapi.PatchOrderResult, error)
+	SetOrderEndDatesFunc func(ctx context.Context, platform, orderID string, endDate time.Time) (*restapi.Order, error)
+
+	// PlatformService
+	PlatformsFunc func(ctx context.Context) ([]restapi.Platform, error)
+
+	// VideofileService
+	VideofilesFunc func(ctx context.Context, assetID string) (*restapi.VideofilesResponse, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Call is a method call recorded by Mock. The context is not recorded.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+var (
+	_ restapi.AssetService     = (*Mock)(nil)
+	_ restapi.OrderService     = (*Mock)(nil)
+	_ restapi.PlatformService  = (*Mock)(nil)
+	_ restapi.VideofileService = (*Mock)(nil)
+)
+
+// Calls returns the recorded calls, in the order they were made.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+func (m *Mock) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+// Asset calls AssetFunc.
+func (m *Mock) Asset(ctx context.Context, platform, assetID string) (*restapi.Asset, error) {
+	m.record("Asset", platform, assetID)
+
+	if m.AssetFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.AssetFunc(ctx, platform, assetID)
+}
+
+// AssetRaw calls AssetRawFunc.
+func (m *Mock) AssetRaw(ctx context.Context, platform, assetID, headerAccept string) ([]byte, error) {
+	m.record("AssetRaw", platform, assetID, headerAccept)
+
+	if m.AssetRawFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.AssetRawFunc(ctx, platform, assetID, headerAccept)
+}
+
+// CreateAsset calls CreateAssetFunc.
+func (m *Mock) CreateAsset(ctx context.Context, platform string, asset *restapi.Asset) (*restapi.Asset, error) {
+	m.record("CreateAsset", platform, asset)
+
+	if m.CreateAssetFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.CreateAssetFunc(ctx, platform, asset)
+}
+
+// UpdateAsset calls UpdateAssetFunc.
+func (m *Mock) UpdateAsset(ctx context.Context, platform string, asset *restapi.Asset) (*restapi.Asset, error) {
+	m.record("UpdateAsset", platform, asset)
+
+	if m.UpdateAssetFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.UpdateAssetFunc(ctx, platform, asset)
+}
+
+// UpdateAssetFields calls UpdateAssetFieldsFunc.
+func (m *Mock) UpdateAssetFields(ctx context.Context, platform, assetID string, values map[string]interface{}) (*restapi.Asset, error) {
+	m.record("UpdateAssetFields", platform, assetID, values)
+
+	if m.UpdateAssetFieldsFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.UpdateAssetFieldsFunc(ctx, platform, assetID, values)
+}
+
+// DeleteAsset calls DeleteAssetFunc.
+func (m *Mock) DeleteAsset(ctx context.Context, platform, assetID string) error {
+	m.record("DeleteAsset", platform, assetID)
+
+	if m.DeleteAssetFunc == nil {
+		return ErrNotMocked
+	}
+
+	return m.DeleteAssetFunc(ctx, platform, assetID)
+}
+
+// SearchAssets calls SearchAssetsFunc.
+func (m *Mock) SearchAssets(ctx context.Context, platform string, query restapi.AssetQuery) iter.Seq2[*restapi.Asset, error] {
+	m.record("SearchAssets", platform, query)
+
+	if m.SearchAssetsFunc == nil {
+		return func(yield func(*restapi.Asset, error) bool) {
+			yield(nil, ErrNotMocked)
+		}
+	}
+
+	return m.SearchAssetsFunc(ctx, platform, query)
+}
+
+// SetAssetMetadata calls SetAssetMetadataFunc.
+func (m *Mock) SetAssetMetadata(ctx context.Context, platform, assetID string, entries restapi.MetadataMap) (restapi.MetadataMap, error) {
+	m.record("SetAssetMetadata", platform, assetID, entries)
+
+	if m.SetAssetMetadataFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.SetAssetMetadataFunc(ctx, platform, assetID, entries)
+}
+
+// UpdateAssetMetadata calls UpdateAssetMetadataFunc.
+func (m *Mock) UpdateAssetMetadata(ctx context.Context, platform, assetID string, changes ...restapi.MetadataChange) (restapi.MetadataMap, error) {
+	m.record("UpdateAssetMetadata", platform, assetID, changes)
+
+	if m.UpdateAssetMetadataFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.UpdateAssetMetadataFunc(ctx, platform, assetID, changes...)
+}
+
+// Order calls OrderFunc.
+func (m *Mock) Order(ctx context.Context, platform, orderID string) (*restapi.Order, error) {
+	m.record("Order", platform, orderID)
+
+	if m.OrderFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.OrderFunc(ctx, platform, orderID)
+}
+
+// CurrentOrders calls CurrentOrdersFunc.
+func (m *Mock) CurrentOrders(ctx context.Context, platform, userID string) ([]*restapi.Order, error) {
+	m.record("CurrentOrders", platform, userID)
+
+	if m.CurrentOrdersFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.CurrentOrdersFunc(ctx, platform, userID)
+}
+
+// OrderHistory calls OrderHistoryFunc.
+func (m *Mock) OrderHistory(ctx context.Context, platform, userID string) ([]*restapi.Order, error) {
+	m.record("OrderHistory", platform, userID)
+
+	if m.OrderHistoryFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.OrderHistoryFunc(ctx, platform, userID)
+}
+
+// CreateOrder calls CreateOrderFunc.
+func (m *Mock) CreateOrder(ctx context.Context, platform, userID, productPaymentID string) (*restapi.Order, error) {
+	m.record("CreateOrder", platform, userID, productPaymentID)
+
+	if m.CreateOrderFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.CreateOrderFunc(ctx, platform, userID, productPaymentID)
+}
+
+// TerminateOrder calls TerminateOrderFunc.
+func (m *Mock) TerminateOrder(ctx context.Context, platform, orderID string, immediately bool) (*restapi.Order, error) {
+	m.record("TerminateOrder", platform, orderID, immediately)
+
+	if m.TerminateOrderFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.TerminateOrderFunc(ctx, platform, orderID, immediately)
+}
+
+// CancelAutoRenew calls CancelAutoRenewFunc.
+func (m *Mock) CancelAutoRenew(ctx context.Context, platform, orderID string) (*restapi.Order, error) {
+	m.record("CancelAutoRenew", platform, orderID)
+
+	if m.CancelAutoRenewFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.CancelAutoRenewFunc(ctx, platform, orderID)
+}
+
+// ReactivateOrder calls ReactivateOrderFunc.
+func (m *Mock) ReactivateOrder(ctx context.Context, platform, orderID string) (*restapi.Order, error) {
+	m.record("ReactivateOrder", platform, orderID)
+
+	if m.ReactivateOrderFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.ReactivateOrderFunc(ctx, platform, orderID)
+}
+
+// PatchOrder calls PatchOrderFunc.
+func (m *Mock) PatchOrder(ctx context.Context, platform, orderID string, values map[string]interface{}, opts restapi.PatchOrderOptions) (*restapi.PatchOrderResult, error) {
+	m.record("PatchOrder", platform, orderID, values, opts)
+
+	if m.PatchOrderFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.PatchOrderFunc(ctx, platform, orderID, values, opts)
+}
+
+// SetOrderEndDates calls SetOrderEndDatesFunc.
+func (m *Mock) SetOrderEndDates(ctx context.Context, platform, orderID string, endDate time.Time) (*restapi.Order, error) {
+	m.record("SetOrderEndDates", platform, orderID, endDate)
+
+	if m.SetOrderEndDatesFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.SetOrderEndDatesFunc(ctx, platform, orderID, endDate)
+}
+
+// Platforms calls PlatformsFunc.
+func (m *Mock) Platforms(ctx context.Context) ([]restapi.Platform, error) {
+	m.record("Platforms")
+
+	if m.PlatformsFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.PlatformsFunc(ctx)
+}
+
+// Videofiles calls VideofilesFunc.
+func (m *Mock) Videofiles(ctx context.Context, assetID string) (*restapi.VideofilesResponse, error) {
+	m.record("Videofiles", assetID)
+
+	if m.VideofilesFunc == nil {
+		return nil, ErrNotMocked
+	}
+
+	return m.VideofilesFunc(ctx, assetID)
+}
@@ -0,0 +1,57 @@
+package vimondtest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TV4/vimond/restapi"
+	"github.com/TV4/vimond/restapi/vimondtest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func assetTitle(ctx context.Context, as restapi.AssetService, assetID string) (string, error) {
+	a, err := as.Asset(ctx, "tv4", assetID)
+	if err != nil {
+		return "", err
+	}
+
+	return a.Title, nil
+}
+
+func TestMock(t *testing.T) {
+	m := &vimondtest.Mock{
+		AssetFunc: func(ctx context.Context, platform, assetID string) (*restapi.Asset, error) {
+			return &restapi.Asset{ID: assetID, Title: "Nyheterna"}, nil
+		},
+	}
+
+	title, err := assetTitle(context.Background(), m, "123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := title, "Nyheterna"; got != want {
+		t.Errorf("title = %q, want %q", got, want)
+	}
+
+	if _, err := m.Order(context.Background(), "tv4", "1"); !errors.Is(err, vimondtest.ErrNotMocked) {
+		t.Errorf("err = %v, want ErrNotMocked", err)
+	}
+
+	for _, err := range m.SearchAssets(context.Background(), "tv4", restapi.AssetQuery{Text: "foo"}) {
+		if !errors.Is(err, vimondtest.ErrNotMocked) {
+			t.Errorf("err = %v, want ErrNotMocked", err)
+		}
+	}
+
+	want := []vimondtest.Call{
+		{Method: "Asset", Args: []interface{}{"tv4", "123"}},
+		{Method: "Order", Args: []interface{}{"tv4", "1"}},
+		{Method: "SearchAssets", Args: []interface{}{"tv4", restapi.AssetQuery{Text: "foo"}}},
+	}
+
+	if got := m.Calls(); !cmp.Equal(got, want) {
+		t.Errorf("calls mismatch (-got +want):\n%s", cmp.Diff(got, want))
+	}
+}
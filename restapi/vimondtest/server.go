@@ -0,0 +1,477 @@
+// Package vimondtest provides an in-memory fake of the Vimond REST API, for
+// testing code that uses the restapi client without network access.
+package vimondtest
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TV4/vimond/restapi"
+)
+
+// Server is a fake Vimond REST API server, seeded with assets, orders,
+// platforms and videofiles using its Add methods.
+//
+// Requests are handled in this order: they are recorded, faults are injected,
+// the SUMO Authorization header is validated if credentials are set, and the
+// Accept header is negotiated, before the request reaches the handler.
+//
+// The JSON responses for v=2 and v=3 are identical, only the Content-Type
+// differs. XML responses are only served for assets.
+type Server struct {
+	// URL is the base URL of the server, to be used with restapi.BaseURL.
+	URL string
+
+	ts  *httptest.Server
+	mux *http.ServeMux
+
+	apiKey string
+	secret string
+
+	mu         sync.Mutex
+	platforms  []restapi.Platform
+	assets     map[key]restapi.Asset
+	orders     map[key]restapi.Order
+	videofiles map[string]restapi.VideofilesResponse
+	faults     []*Fault
+	requests   []Request
+}
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+}
+
+// Fault is a failure injected into the responses of the server.
+type Fault struct {
+	// Path limits the fault to requests with the given path prefix, all
+	// requests if empty.
+	Path string
+
+	// Count is the number of requests the fault applies to, unlimited if 0.
+	Count int
+
+	// Latency delays the response.
+	Latency time.Duration
+
+	// StatusCode is the status of the response, which is not otherwise
+	// handled. Zero means that only the latency is injected.
+	StatusCode int
+
+	// RetryAfter is sent as the Retry-After header, rounded up to whole
+	// seconds since the header has no finer resolution.
+	RetryAfter time.Duration
+}
+
+type key struct {
+	platform string
+	id       string
+}
+
+type format struct {
+	xml     bool
+	version string
+}
+
+// NewServer starts a fake Vimond REST API server. The caller should call Close
+// when finished, to shut it down.
+func NewServer(options ...func(*Server)) *Server {
+	s := &Server{
+		mux:        http.NewServeMux(),
+		assets:     map[key]restapi.Asset{},
+		orders:     map[key]restapi.Order{},
+		videofiles: map[string]restapi.VideofilesResponse{},
+	}
+
+	for _, f := range options {
+		f(s)
+	}
+
+	s.mux.HandleFunc("GET /api/admin/platforms", s.handlePlatforms)
+	s.mux.HandleFunc("GET /api/admin/asset/{assetID}/videofiles", s.handleVideofiles)
+	s.mux.HandleFunc("GET /api/{platform}/asset/{assetID}", s.handleAsset)
+	s.mux.HandleFunc("DELETE /api/{platform}/asset/{assetID}", s.handleDeleteAsset)
+	s.mux.HandleFunc("GET /api/{platform}/order/{orderID}", s.handleOrder)
+	s.mux.HandleFunc("GET /api/{platform}/user/{userID}/orders/current", s.handleUserOrders(true))
+	s.mux.HandleFunc("GET /api/{platform}/user/{userID}/orders/history", s.handleUserOrders(false))
+
+	s.ts = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.ts.URL
+
+	return s
+}
+
+// Credentials makes the server require requests to be signed with the given
+// api key and secret.
+func Credentials(apiKey, secret string) func(*Server) {
+	return func(s *Server) {
+		s.apiKey = apiKey
+		s.secret = secret
+	}
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.ts.Close()
+}
+
+// Client returns a client for the server, using its credentials. The given
+// options are applied after BaseURL and Credentials.
+func (s *Server) Client(options ...func(*restapi.Client)) *restapi.Client {
+	return restapi.NewClient(append([]func(*restapi.Client){
+		restapi.BaseURL(s.URL),
+		restapi.Credentials(s.apiKey, s.secret),
+	}, options...)...)
+}
+
+// HandleFunc registers a handler for endpoints the server does not fake, using
+// http.ServeMux patterns. It panics if the pattern conflicts with a built in
+// endpoint.
+func (s *Server) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// AddPlatform adds a platform.
+func (s *Server) AddPlatform(p restapi.Platform) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.platforms = append(s.platforms, p)
+}
+
+// AddAsset adds or replaces an asset of a platform. It panics if the IDs of
+// the asset are not numeric.
+func (s *Server) AddAsset(platform string, a restapi.Asset) {
+	mustBeNumeric("asset id", a.ID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.assets[key{platform, a.ID}] = a
+}
+
+// AddOrder adds or replaces an order of a platform. It panics if the IDs of
+// the order are not numeric.
+func (s *Server) AddOrder(platform string, o restapi.Order) {
+	mustBeNumeric("order id", o.ID)
+	mustBeNumeric("user id", o.UserID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders[key{platform, o.ID}] = o
+}
+
+// AddVideofiles adds or replaces the videofiles of an asset.
+func (s *Server) AddVideofiles(vr restapi.VideofilesResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.videofiles[strconv.Itoa(vr.AssetID)] = vr
+}
+
+// InjectFault adds a fault to the responses of the server. Faults are applied
+// in the order they are added, and the first matching fault is used.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Requests returns the requests received by the server, including those
+// failed by faults or authorization.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+	})
+	fault := s.fault(r.URL.Path)
+	s.mu.Unlock()
+
+	if fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if fault.StatusCode != 0 {
+			if fault.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int((fault.RetryAfter+time.Second-1)/time.Second)))
+			}
+
+			writeError(w, fault.StatusCode, "INJECTED_FAULT", "injected fault")
+			return
+		}
+	}
+
+	if s.apiKey != "" || s.secret != "" {
+		if err := s.authorize(r); err != nil {
+			writeError(w, http.StatusUnauthorized, "AUTHENTICATION_FAILED", err.Error())
+			return
+		}
+	}
+
+	f, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		writeError(w, http.StatusNotAcceptable, "NOT_ACCEPTABLE", "unsupported Accept header "+r.Header.Get("Accept"))
+		return
+	}
+
+	r = r.WithContext(withFormat(r.Context(), f))
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// fault returns the first fault matching path, counting it as used. It must
+// be called with s.mu held.
+func (s *Server) fault(path string) *Fault {
+	for i, f := range s.faults {
+		if !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+
+		if f.Count > 0 {
+			if f.Count--; f.Count == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+// authorize validates the SUMO HMAC-SHA1 signature of the method, path and
+// Date header of a request.
+func (s *Server) authorize(r *http.Request) error {
+	date := r.Header.Get("Date")
+
+	if _, err := time.Parse(time.RFC1123Z, date); err != nil {
+		return fmt.Errorf("invalid Date header %q", date)
+	}
+
+	credentials, ok := strings.CutPrefix(r.Header.Get("Authorization"), "SUMO ")
+	if !ok {
+		return fmt.Errorf("missing SUMO Authorization header")
+	}
+
+	apiKey, signature, _ := strings.Cut(credentials, ":")
+	if apiKey != s.apiKey {
+		return fmt.Errorf("unknown api key")
+	}
+
+	mac := hmac.New(sha1.New, []byte(s.secret))
+	fmt.Fprintf(mac, "%s\n%s\n%s", r.Method, r.URL.Path, date)
+
+	if !hmac.Equal([]byte(signature), []byte(base64.StdEncoding.EncodeToString(mac.Sum(nil)))) {
+		return fmt.Errorf("invalid signature")
+	}
+
+	return nil
+}
+
+func (s *Server) handlePlatforms(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	platforms := make([]wirePlatform, 0, len(s.platforms))
+
+	for _, p := range s.platforms {
+		platforms = append(platforms, wirePlatform{ID: p.ID, Name: p.Name})
+	}
+
+	writeJSON(w, r, platforms)
+}
+
+func (s *Server) handleAsset(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	a, ok := s.assets[key{r.PathValue("platform"), r.PathValue("assetID")}]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "ASSET_NOT_FOUND", "asset "+r.PathValue("assetID")+" not found")
+		return
+	}
+
+	if formatFrom(r.Context()).xml {
+		writeXML(w, newXMLAsset(a))
+		return
+	}
+
+	writeJSON(w, r, newWireAsset(a))
+}
+
+func (s *Server) handleDeleteAsset(w http.ResponseWriter, r *http.Request) {
+	k := key{r.PathValue("platform"), r.PathValue("assetID")}
+
+	s.mu.Lock()
+	_, ok := s.assets[k]
+	delete(s.assets, k)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "ASSET_NOT_FOUND", "asset "+k.id+" not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleOrder(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	o, ok := s.orders[key{r.PathValue("platform"), r.PathValue("orderID")}]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "ORDER_NOT_FOUND", "order "+r.PathValue("orderID")+" not found")
+		return
+	}
+
+	writeJSON(w, r, newWireOrder(o))
+}
+
+// handleUserOrders returns the orders of a user, ordered by ID. Current
+// orders are those without an end date, or with an end date in the future.
+func (s *Server) handleUserOrders(current bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		platform, userID := r.PathValue("platform"), r.PathValue("userID")
+		now := time.Now()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		orders := []wireOrder{}
+
+		for k, o := range s.orders {
+			if k.platform != platform || o.UserID != userID {
+				continue
+			}
+
+			if current && !o.EndDate.IsZero() && !o.EndDate.After(now) {
+				continue
+			}
+
+			orders = append(orders, newWireOrder(o))
+		}
+
+		sortOrders(orders)
+
+		writeJSON(w, r, orders)
+	}
+}
+
+func (s *Server) handleVideofiles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	vr, ok := s.videofiles[r.PathValue("assetID")]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "ASSET_NOT_FOUND", "asset "+r.PathValue("assetID")+" not found")
+		return
+	}
+
+	writeJSON(w, r, vr)
+}
+
+// negotiate returns the response format for an Accept header. Missing and
+// wildcard headers get JSON v=3.
+func negotiate(accept string) (format, bool) {
+	if accept == "" {
+		return format{version: "3"}, true
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case "application/json", "*/*", "application/*":
+			switch v := params["v"]; v {
+			case "":
+				return format{version: "3"}, true
+			case "2", "3":
+				return format{version: v}, true
+			}
+		case "application/xml":
+			return format{xml: true}, true
+		}
+	}
+
+	return format{}, false
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	f := formatFrom(r.Context())
+
+	if f.xml {
+		writeError(w, http.StatusNotAcceptable, "NOT_ACCEPTABLE", "XML is not supported by "+r.URL.Path)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; v="+f.version+"; charset=utf-8")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code, description string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]string{
+			"code":        code,
+			"description": description,
+		},
+	})
+}
+
+func mustBeNumeric(name, s string) {
+	if _, err := strconv.Atoi(s); err != nil {
+		panic(fmt.Sprintf("vimondtest: invalid %s %q", name, s))
+	}
+}
@@ -0,0 +1,255 @@
+package vimondtest_test
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TV4/vimond/restapi"
+	"github.com/TV4/vimond/restapi/vimondtest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newServer(t *testing.T) *vimondtest.Server {
+	s := vimondtest.NewServer(vimondtest.Credentials("key", "secret"))
+	t.Cleanup(s.Close)
+
+	s.AddPlatform(restapi.Platform{ID: 1, Name: "tv4"})
+	s.AddAsset("tv4", restapi.Asset{
+		ID:          "123",
+		CategoryID:  "10",
+		AssetTypeID: "1",
+		Title:       "Nyheterna",
+		Metadata: restapi.AssetMetadata{
+			Entries: restapi.MetadataEntries{
+				Title: restapi.LocalizedField{{Lang: "*", Value: "Nyheterna"}},
+			},
+		},
+	})
+	s.AddOrder("tv4", restapi.Order{ID: "1", UserID: "7", ProductName: "Premium"})
+	s.AddOrder("tv4", restapi.Order{ID: "2", UserID: "7", ProductName: "Sport", EndDate: time.Now().Add(-time.Hour)})
+	s.AddVideofiles(restapi.VideofilesResponse{
+		AssetID:    123,
+		Title:      "Nyheterna",
+		Videofiles: []restapi.Videofile{{Bitrate: 1000, MediaFormat: "HLS", URL: "https://cdn/123.m3u8"}},
+	})
+
+	return s
+}
+
+func TestServer(t *testing.T) {
+	s := newServer(t)
+	c := s.Client()
+	ctx := context.Background()
+
+	t.Run("Asset", func(t *testing.T) {
+		a, err := c.Asset(ctx, "tv4", "123")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := a.CategoryID, "10"; got != want {
+			t.Errorf("a.CategoryID = %q, want %q", got, want)
+		}
+
+		if got, want := a.Metadata.Entries.Title.Value("*"), "Nyheterna"; got != want {
+			t.Errorf("title = %q, want %q", got, want)
+		}
+
+		if _, err := c.Asset(ctx, "tv4", "456"); !errors.Is(err, restapi.ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("Orders", func(t *testing.T) {
+		current, err := c.CurrentOrders(ctx, "tv4", "7")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := len(current), 1; got != want {
+			t.Fatalf("len(current) = %d, want %d", got, want)
+		}
+
+		history, err := c.OrderHistory(ctx, "tv4", "7")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := len(history), 2; got != want {
+			t.Fatalf("len(history) = %d, want %d", got, want)
+		}
+
+		o, err := c.Order(ctx, "tv4", "2")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := o.ProductName, "Sport"; got != want {
+			t.Errorf("o.ProductName = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Platforms", func(t *testing.T) {
+		platforms, err := c.Platforms(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := []restapi.Platform{{ID: 1, Name: "tv4"}}; !cmp.Equal(platforms, want) {
+			t.Errorf("platforms = %v, want %v", platforms, want)
+		}
+	})
+
+	t.Run("Videofiles", func(t *testing.T) {
+		vr, err := c.Videofiles(ctx, "123")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := vr.Videofiles[0].URL, "https://cdn/123.m3u8"; got != want {
+			t.Errorf("URL = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestServerAuthorization(t *testing.T) {
+	s := newServer(t)
+
+	for name, c := range map[string]*restapi.Client{
+		"Unsigned":    restapi.NewClient(restapi.BaseURL(s.URL)),
+		"WrongSecret": restapi.NewClient(restapi.BaseURL(s.URL), restapi.Credentials("key", "wrong")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := c.Asset(context.Background(), "tv4", "123")
+
+			var apiErr *restapi.APIError
+
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+				t.Fatalf("err = %v, want 401", err)
+			}
+		})
+	}
+}
+
+func TestServerAccept(t *testing.T) {
+	s := newServer(t)
+
+	for _, accept := range []string{
+		"application/json; v=3; charset=utf-8",
+		"application/json; v=2; charset=utf-8",
+		"application/json; charset=utf-8",
+	} {
+		b, err := s.Client().AssetRaw(context.Background(), "tv4", "123", accept)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", accept, err)
+		}
+
+		if !strings.Contains(string(b), `"id":123`) {
+			t.Errorf("unexpected body for %q: %s", accept, b)
+		}
+	}
+
+	b, err := s.Client().AssetRaw(context.Background(), "tv4", "123", "application/xml; charset=utf-8")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var asset struct {
+		ID       string `xml:"id,attr"`
+		Title    string `xml:"title"`
+		Metadata []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"metadata>entry"`
+	}
+
+	if err := xml.Unmarshal(b, &asset); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if asset.ID != "123" || asset.Title != "Nyheterna" || len(asset.Metadata) != 1 {
+		t.Errorf("unexpected XML asset %+v", asset)
+	}
+
+	if _, err := s.Client().AssetRaw(context.Background(), "tv4", "123", "text/html"); err == nil {
+		t.Errorf("expected error for unsupported Accept header")
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	t.Run("RetryAfter", func(t *testing.T) {
+		s := newServer(t)
+
+		s.InjectFault(vimondtest.Fault{
+			Path:       "/api/tv4/asset/",
+			Count:      1,
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: time.Second,
+		})
+
+		c := s.Client(restapi.Retry(restapi.RetryPolicy{MaxAttempts: 2, MaxBackoff: 10 * time.Millisecond}))
+
+		if _, err := c.Asset(context.Background(), "tv4", "123"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		requests := s.Requests()
+
+		if got, want := len(requests), 2; got != want {
+			t.Fatalf("len(requests) = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("RetryAfterRoundedUp", func(t *testing.T) {
+		s := newServer(t)
+
+		s.InjectFault(vimondtest.Fault{StatusCode: http.StatusTooManyRequests, RetryAfter: 100 * time.Millisecond})
+
+		resp, err := http.Get(s.URL + "/api/admin/platforms")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if got, want := resp.Header.Get("Retry-After"), "1"; got != want {
+			t.Errorf("Retry-After = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("ServerError", func(t *testing.T) {
+		s := newServer(t)
+
+		s.InjectFault(vimondtest.Fault{StatusCode: http.StatusInternalServerError})
+
+		_, err := s.Client().Platforms(context.Background())
+
+		var apiErr *restapi.APIError
+
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("err = %v, want 500", err)
+		}
+
+		s.ClearFaults()
+
+		if _, err := s.Client().Platforms(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		s := newServer(t)
+
+		s.InjectFault(vimondtest.Fault{Latency: time.Second})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		if _, err := s.Client().Platforms(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
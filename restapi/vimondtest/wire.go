@@ -0,0 +1,195 @@
+package vimondtest
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/TV4/vimond/restapi"
+)
+
+type formatKey struct{}
+
+func withFormat(ctx context.Context, f format) context.Context {
+	return context.WithValue(ctx, formatKey{}, f)
+}
+
+func formatFrom(ctx context.Context) format {
+	f, _ := ctx.Value(formatKey{}).(format)
+
+	return f
+}
+
+// atoi returns the number in s, or 0 for empty or non-numeric IDs.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+
+	return n
+}
+
+type wirePlatform struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type assetAlias restapi.Asset
+
+// wireAsset is an asset as encoded by Vimond, with numeric IDs.
+type wireAsset struct {
+	AssetTypeID int `json:"assetTypeId"`
+	CategoryID  int `json:"categoryId"`
+	ChannelID   int `json:"channelId"`
+	ID          int `json:"id"`
+
+	*assetAlias
+}
+
+func newWireAsset(a restapi.Asset) wireAsset {
+	return wireAsset{
+		AssetTypeID: atoi(a.AssetTypeID),
+		CategoryID:  atoi(a.CategoryID),
+		ChannelID:   atoi(a.ChannelID),
+		ID:          atoi(a.ID),
+		assetAlias:  (*assetAlias)(&a),
+	}
+}
+
+// xmlAsset is a subset of the fields of an asset as encoded by Vimond in XML.
+type xmlAsset struct {
+	XMLName xml.Name `xml:"asset"`
+
+	ID          int `xml:"id,attr"`
+	CategoryID  int `xml:"categoryId,attr"`
+	AssetTypeID int `xml:"assetTypeId"`
+	ChannelID   int `xml:"channelId"`
+
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	ImageURL    string `xml:"imageUrl,omitempty"`
+	Duration    int    `xml:"duration"`
+	Archive     bool   `xml:"archive"`
+	Live        bool   `xml:"live"`
+
+	CreateTime *time.Time `xml:"createTime,omitempty"`
+	UpdateTime *time.Time `xml:"updateTime,omitempty"`
+
+	Metadata []xmlMetadataEntry `xml:"metadata>entry"`
+}
+
+type xmlMetadataEntry struct {
+	Name   string              `xml:"name,attr"`
+	Values []xmlLocalizedValue `xml:"value"`
+}
+
+type xmlLocalizedValue struct {
+	Lang  string `xml:"lang,attr"`
+	Value string `xml:",chardata"`
+}
+
+func newXMLAsset(a restapi.Asset) xmlAsset {
+	date := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+
+		return &t
+	}
+
+	xa := xmlAsset{
+		ID:          atoi(a.ID),
+		CategoryID:  atoi(a.CategoryID),
+		AssetTypeID: atoi(a.AssetTypeID),
+		ChannelID:   atoi(a.ChannelID),
+		Title:       a.Title,
+		Description: a.Description,
+		ImageURL:    a.ImageURL,
+		Duration:    a.Duration,
+		Archive:     a.Archive,
+		Live:        a.Live,
+		CreateTime:  date(a.CreateTime),
+		UpdateTime:  date(a.UpdateTime),
+	}
+
+	entries := metadataEntries(a.Metadata)
+
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		entry := xmlMetadataEntry{Name: name}
+
+		for _, lv := range entries[name] {
+			entry.Values = append(entry.Values, xmlLocalizedValue{Lang: lv.Lang, Value: lv.Value})
+		}
+
+		xa.Metadata = append(xa.Metadata, entry)
+	}
+
+	return xa
+}
+
+// metadataEntries returns all entries of am, merged as they are when encoded
+// in JSON.
+func metadataEntries(am restapi.AssetMetadata) restapi.MetadataMap {
+	var v struct {
+		Entries restapi.MetadataMap `json:"entries"`
+	}
+
+	if b, err := json.Marshal(am); err == nil {
+		json.Unmarshal(b, &v)
+	}
+
+	return v.Entries
+}
+
+// wireOrder is an order as encoded by Vimond, with numeric IDs.
+type wireOrder struct {
+	AccessEndDate     time.Time `json:"accessEndDate"`
+	AutoRenew         bool      `json:"autoRenew"`
+	Currency          string    `json:"currency"`
+	EarlyEndDate      time.Time `json:"earlyEndDate"`
+	EndDate           time.Time `json:"endDate"`
+	ID                int       `json:"id"`
+	PaymentProviderID int       `json:"paymentProviderId"`
+	Period            string    `json:"period"`
+	PlatformID        int       `json:"platformId"`
+	Price             float64   `json:"price"`
+	ProductName       string    `json:"productName"`
+	ProductPaymentID  int       `json:"productPaymentID"`
+	Referrer          string    `json:"referrer"`
+	StartDate         time.Time `json:"startDate"`
+	Status            string    `json:"status"`
+	TerminationReason string    `json:"terminationReason"`
+	UserID            int       `json:"userId"`
+	VoucherCode       string    `json:"voucherCode"`
+}
+
+func newWireOrder(o restapi.Order) wireOrder {
+	return wireOrder{
+		AccessEndDate:     o.AccessEndDate,
+		AutoRenew:         o.AutoRenew,
+		Currency:          o.Currency,
+		EarlyEndDate:      o.EarlyEndDate,
+		EndDate:           o.EndDate,
+		ID:                atoi(o.ID),
+		PaymentProviderID: atoi(o.PaymentProviderID),
+		Period:            o.Period,
+		PlatformID:        atoi(o.PlatformID),
+		Price:             o.Price,
+		ProductName:       o.ProductName,
+		ProductPaymentID:  atoi(o.ProductPaymentID),
+		Referrer:          o.Referrer,
+		StartDate:         o.StartDate,
+		Status:            o.Status,
+		TerminationReason: o.TerminationReason,
+		UserID:            atoi(o.UserID),
+		VoucherCode:       o.VoucherCode,
+	}
+}
+
+func sortOrders(orders []wireOrder) {
+	slices.SortFunc(orders, func(a, b wireOrder) int {
+		return a.ID - b.ID
+	})
+}
@@ -0,0 +1,200 @@
+package restapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Voucher is a voucher code that can be redeemed for a product payment.
+type Voucher struct {
+	Code             string
+	ProductPaymentID string
+	Campaign         string
+	StartDate        time.Time
+	ExpiryDate       time.Time
+	Usages           int
+	MaxUsages        int
+}
+
+// VoucherBatch describes a batch of vouchers to generate.
+type VoucherBatch struct {
+	ProductPaymentID string
+	Campaign         string
+	Count            int
+	Prefix           string
+	StartDate        time.Time
+	ExpiryDate       time.Time
+	MaxUsages        int
+}
+
+// Voucher returns information about a voucher code. Unknown codes result in
+// an error matching both ErrVoucherInvalid and ErrNotFound.
+func (c *Client) Voucher(ctx context.Context, platform, code string) (_ *Voucher, err error) {
+	ctx, op := c.startOperation(ctx, "Voucher", platform)
+	defer op.end(&err)
+
+	resp, err := c.get(ctx, c.voucherPath(platform, code), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newVoucherAPIError(resp)
+	}
+
+	return parseVoucher(resp.Body)
+}
+
+// ValidateVoucher checks that a voucher code can be redeemed for the given
+// product payment. The returned error matches ErrVoucherExpired,
+// ErrVoucherUsed or ErrVoucherInvalid if the code cannot be redeemed.
+func (c *Client) ValidateVoucher(ctx context.Context, platform, code, productPaymentID string) (err error) {
+	ctx, op := c.startOperation(ctx, "ValidateVoucher", platform)
+	defer op.end(&err)
+
+	resp, err := c.get(ctx, c.voucherPath(platform, code)+"/validate", url.Values{"productPaymentId": {productPaymentID}})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return newVoucherAPIError(resp)
+	}
+
+	return nil
+}
+
+// RedeemVoucher redeems a voucher code for a user by creating an order for the
+// given product payment. The returned error matches ErrVoucherExpired,
+// ErrVoucherUsed or ErrVoucherInvalid if the code cannot be redeemed.
+func (c *Client) RedeemVoucher(ctx context.Context, platform, code, userID, productPaymentID string) (_ *Order, err error) {
+	ctx, op := c.startOperation(ctx, "RedeemVoucher", platform)
+	defer op.end(&err)
+
+	return c.createOrder(ctx, platform, userID, productPaymentID, code)
+}
+
+// GenerateVouchers generates a batch of voucher codes.
+func (c *Client) GenerateVouchers(ctx context.Context, platform string, batch VoucherBatch) (_ []Voucher, err error) {
+	ctx, op := c.startOperation(ctx, "GenerateVouchers", platform)
+	defer op.end(&err)
+
+	productPaymentID, err := strconv.Atoi(batch.ProductPaymentID)
+	if err != nil {
+		return nil, fmt.Errorf("vimond/restapi: invalid product payment id %q", batch.ProductPaymentID)
+	}
+
+	date := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+
+		return &t
+	}
+
+	body, err := json.Marshal(struct {
+		ProductPaymentID int        `json:"productPaymentId"`
+		Pool             string     `json:"pool,omitempty"`
+		Count            int        `json:"numberOfVouchers"`
+		Prefix           string     `json:"prefix,omitempty"`
+		StartDate        *time.Time `json:"startDate,omitempty"`
+		EndDate          *time.Time `json:"endDate,omitempty"`
+		MaxUsages        int        `json:"maxUsages,omitempty"`
+	}{
+		ProductPaymentID: productPaymentID,
+		Pool:             batch.Campaign,
+		Count:            batch.Count,
+		Prefix:           batch.Prefix,
+		StartDate:        date(batch.StartDate),
+		EndDate:          date(batch.ExpiryDate),
+		MaxUsages:        batch.MaxUsages,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.post(ctx, fmt.Sprintf("/api/%s/voucher/generate", platform), url.Values{}, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.CopyN(ioutil.Discard, resp.Body, 64)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, newAPIError(resp)
+	}
+
+	return parseVouchers(resp.Body)
+}
+
+func (c *Client) voucherPath(platform, code string) string {
+	return fmt.Sprintf("/api/%s/voucher/%s", platform, url.PathEscape(code))
+}
+
+type vimondVoucher struct {
+	Code             string    `json:"code"`
+	ProductPaymentID int       `json:"productPaymentId"`
+	Pool             string    `json:"pool"`
+	StartDate        time.Time `json:"startDate"`
+	EndDate          time.Time `json:"endDate"`
+	Usages           int       `json:"usages"`
+	MaxUsages        int       `json:"maxUsages"`
+}
+
+func (vv *vimondVoucher) voucher() Voucher {
+	return Voucher{
+		Code:             vv.Code,
+		ProductPaymentID: strconv.Itoa(vv.ProductPaymentID),
+		Campaign:         vv.Pool,
+		StartDate:        vv.StartDate,
+		ExpiryDate:       vv.EndDate,
+		Usages:           vv.Usages,
+		MaxUsages:        vv.MaxUsages,
+	}
+}
+
+func parseVoucher(r io.Reader) (*Voucher, error) {
+	var vv vimondVoucher
+
+	if err := json.NewDecoder(r).Decode(&vv); err != nil {
+		return nil, err
+	}
+
+	v := vv.voucher()
+
+	return &v, nil
+}
+
+func parseVouchers(r io.Reader) ([]Voucher, error) {
+	var resp []vimondVoucher
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	vouchers := make([]Voucher, 0, len(resp))
+
+	for n := range resp {
+		vouchers = append(vouchers, resp[n].voucher())
+	}
+
+	return vouchers, nil
+}
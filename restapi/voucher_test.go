@@ -0,0 +1,178 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVoucher(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.EscapedPath(), "/api/tv4/voucher/FOO%2FCODE"; got != want {
+			t.Errorf("r.URL.EscapedPath() = %q, want %q", got, want)
+		}
+
+		w.Write([]byte(`{"code":"FOO/CODE","productPaymentId":56,"pool":"foo-campaign","endDate":"2000-01-02T00:00:00Z","usages":1,"maxUsages":2}`))
+	})
+	defer ts.Close()
+
+	voucher, err := c.Voucher(context.Background(), "tv4", "FOO/CODE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &Voucher{
+		Code:             "FOO/CODE",
+		ProductPaymentID: "56",
+		Campaign:         "foo-campaign",
+		ExpiryDate:       time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+		Usages:           1,
+		MaxUsages:        2,
+	}
+
+	if !cmp.Equal(voucher, want) {
+		t.Errorf("voucher = %+v, want %+v", voucher, want)
+	}
+}
+
+func TestVoucherNotFound(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer ts.Close()
+
+	_, err := c.Voucher(context.Background(), "tv4", "FOO-CODE")
+
+	for _, want := range []error{ErrVoucherInvalid, ErrNotFound} {
+		if !errors.Is(err, want) {
+			t.Errorf("Voucher: err = %v, want it to match %v", err, want)
+		}
+	}
+
+	if err := c.ValidateVoucher(context.Background(), "tv4", "FOO-CODE", "56"); !errors.Is(err, ErrVoucherInvalid) {
+		t.Errorf("ValidateVoucher: err = %v, want it to match %v", err, ErrVoucherInvalid)
+	}
+
+	if _, err := c.Order(context.Background(), "tv4", "123"); errors.Is(err, ErrVoucherInvalid) {
+		t.Errorf("Order: err = %v, want it not to match %v", err, ErrVoucherInvalid)
+	}
+}
+
+func TestValidateVoucher(t *testing.T) {
+	for _, tt := range []struct {
+		code   string
+		status int
+		want   error
+	}{
+		{"", http.StatusOK, nil},
+		{"VOUCHER_EXPIRED", http.StatusBadRequest, ErrVoucherExpired},
+		{"VOUCHER_ALREADY_USED", http.StatusBadRequest, ErrVoucherUsed},
+		{"VOUCHER_NOT_FOUND", http.StatusNotFound, ErrVoucherInvalid},
+	} {
+		t.Run(tt.code, func(t *testing.T) {
+			ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.URL.Query().Get("productPaymentId"), "56"; got != want {
+					t.Errorf("productPaymentId = %q, want %q", got, want)
+				}
+
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"code": tt.code}})
+			})
+			defer ts.Close()
+
+			err := c.ValidateVoucher(context.Background(), "tv4", "FOO-CODE", "56")
+
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedeemVoucher(t *testing.T) {
+	ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/api/tv4/order/345678/create"; got != want {
+			t.Errorf("r.URL.Path = %q, want %q", got, want)
+		}
+
+		var body map[string]string
+
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if got, want := body, map[string]string{"productPaymentId": "56", "voucherCode": "FOO-CODE"}; !cmp.Equal(got, want) {
+			t.Errorf("body = %v, want %v", got, want)
+		}
+
+		w.Write([]byte(`{"id":123456,"voucherCode":"FOO-CODE"}`))
+	})
+	defer ts.Close()
+
+	order, err := c.RedeemVoucher(context.Background(), "tv4", "FOO-CODE", "345678", "56")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := order.VoucherCode, "FOO-CODE"; got != want {
+		t.Errorf("order.VoucherCode = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateVouchers(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ts, c := testServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+
+			json.NewDecoder(r.Body).Decode(&body)
+
+			want := map[string]interface{}{
+				"productPaymentId": float64(56),
+				"pool":             "foo-campaign",
+				"numberOfVouchers": float64(2),
+				"endDate":          "2000-01-02T00:00:00Z",
+			}
+
+			if !cmp.Equal(body, want) {
+				t.Errorf("body = %v, want %v", body, want)
+			}
+
+			w.Write([]byte(`[{"code":"A","productPaymentId":56},{"code":"B","productPaymentId":56}]`))
+		})
+		defer ts.Close()
+
+		vouchers, err := c.GenerateVouchers(context.Background(), "tv4", VoucherBatch{
+			ProductPaymentID: "56",
+			Campaign:         "foo-campaign",
+			Count:            2,
+			ExpiryDate:       time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := len(vouchers), 2; got != want {
+			t.Fatalf("got %d vouchers, want %d", got, want)
+		}
+
+		if got, want := vouchers[1].Code, "B"; got != want {
+			t.Errorf("vouchers[1].Code = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("InvalidProductPaymentID", func(t *testing.T) {
+		if _, err := NewClient().GenerateVouchers(context.Background(), "tv4", VoucherBatch{}); err == nil {
+			t.Fatal("err is nil")
+		}
+	})
+}